@@ -0,0 +1,514 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/build"
+	"go/format"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"maps"
+	"path"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+const (
+	funPath   = "github.com/rprtr258/fun"
+	xjsonPath = "github.com/rprtr258/fun/exp/json"
+)
+
+// field is JSON field of struct, possibly promoted from embedded struct.
+type field struct {
+	name string // name in JSON
+	path string // selector of field in Go, like Base.ID
+	typ  types.Type
+	// options of json tag
+	omitEmpty, asString bool
+}
+
+type generator struct {
+	pkg     *types.Package
+	imports map[string]string // package names by path
+	queue   []*types.Named    // struct types to generate decoders for
+}
+
+// load type checks package in dir, skipping output file, since it might be stale.
+func load(dir, output string) (*types.Package, error) {
+	bp, err := build.ImportDir(dir, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	fset := token.NewFileSet()
+	var files []*ast.File
+	for _, name := range bp.GoFiles {
+		if name == output {
+			continue
+		}
+
+		f, err := parser.ParseFile(fset, filepath.Join(dir, name), nil, 0)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, f)
+	}
+
+	var errs []types.Error
+	conf := types.Config{
+		Importer: importer.ForCompiler(fset, "source", nil),
+		Error: func(err error) {
+			errs = append(errs, err.(types.Error))
+		},
+	}
+	pkg, _ := conf.Check(bp.ImportPath, fset, files, nil)
+
+	// files referring to generated decoders, which are skipped, can't be type checked
+	usesGenerated := usingGenerated(fset, files)
+	for _, err := range errs {
+		if !usesGenerated[fset.File(err.Pos)] {
+			return nil, err
+		}
+	}
+	return pkg, nil
+}
+
+// usingGenerated returns files which refer to decoders of types declared in files.
+func usingGenerated(fset *token.FileSet, files []*ast.File) map[*token.File]bool {
+	decoders := map[string]bool{}
+	for _, f := range files {
+		for _, decl := range f.Decls {
+			if gd, ok := decl.(*ast.GenDecl); ok && gd.Tok == token.TYPE {
+				for _, spec := range gd.Specs {
+					decoders["decoder"+spec.(*ast.TypeSpec).Name.Name] = true
+				}
+			}
+		}
+	}
+
+	res := map[*token.File]bool{}
+	for _, f := range files {
+		ast.Inspect(f, func(n ast.Node) bool {
+			if id, ok := n.(*ast.Ident); ok && decoders[id.Name] {
+				res[fset.File(f.Pos())] = true
+			}
+			return true
+		})
+	}
+	return res
+}
+
+// generate returns source of decoders of given types of package in dir.
+func generate(dir string, typeNames []string, output string) ([]byte, error) {
+	pkg, err := load(dir, output)
+	if err != nil {
+		return nil, err
+	}
+
+	g := &generator{
+		pkg:     pkg,
+		imports: map[string]string{xjsonPath: "xjson"},
+	}
+	for _, name := range typeNames {
+		tn, ok := pkg.Scope().Lookup(name).(*types.TypeName)
+		if !ok {
+			return nil, fmt.Errorf("type %s not found", name)
+		}
+
+		named, ok := tn.Type().(*types.Named)
+		if !ok {
+			return nil, fmt.Errorf("type %s is not a struct", name)
+		}
+		if _, ok := named.Underlying().(*types.Struct); !ok {
+			return nil, fmt.Errorf("type %s is not a struct", name)
+		}
+		if named.TypeParams().Len() > 0 {
+			return nil, fmt.Errorf("type %s is generic, which is not supported", name)
+		}
+		g.enqueue(named)
+	}
+
+	var decls, inits bytes.Buffer
+	// queue grows while struct types of fields are found
+	for i := 0; i < len(g.queue); i++ {
+		named := g.queue[i]
+		expr, err := g.object(named, named.Underlying().(*types.Struct))
+		if err != nil {
+			return nil, fmt.Errorf("type %s: %w", named.Obj().Name(), err)
+		}
+
+		fmt.Fprintf(&decls, "\t%s %s\n", decoderName(named), g.decoderType(named))
+		fmt.Fprintf(&inits, "\t%s = %s\n", decoderName(named), expr)
+	}
+
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "// Code generated by \"jsondecgen -type %s\"; DO NOT EDIT.\n\n", strings.Join(typeNames, ","))
+	fmt.Fprintf(&b, "package %s\n\n", pkg.Name())
+	g.writeImports(&b)
+	fmt.Fprintf(&b, "var (\n%s)\n\n", decls.String())
+	// decoders are made in init, since they might refer to each other
+	fmt.Fprintf(&b, "func init() {\n%s}\n", inits.String())
+
+	src, err := format.Source(b.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("format generated code: %w\n%s", err, b.String())
+	}
+	// one-line function literals with multiline parameters are split only on second pass
+	return format.Source(src)
+}
+
+func (g *generator) writeImports(b *bytes.Buffer) {
+	paths := slices.Sorted(maps.Keys(g.imports))
+	// standard library goes first, as goimports does
+	isStd := func(p string) bool {
+		return !strings.Contains(strings.Split(p, "/")[0], ".")
+	}
+
+	b.WriteString("import (\n")
+	for i, std := range []bool{true, false} {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		for _, p := range paths {
+			if isStd(p) != std {
+				continue
+			}
+
+			if name := g.imports[p]; name != path.Base(p) {
+				fmt.Fprintf(b, "\t%s %q\n", name, p)
+			} else {
+				fmt.Fprintf(b, "\t%q\n", p)
+			}
+		}
+	}
+	b.WriteString(")\n\n")
+}
+
+func decoderName(named *types.Named) string {
+	return "decoder" + named.Obj().Name()
+}
+
+func (g *generator) enqueue(named *types.Named) {
+	if !slices.Contains(g.queue, named) {
+		g.queue = append(g.queue, named)
+	}
+}
+
+// use returns package name to qualify identifiers of package with, importing it.
+func (g *generator) use(path, name string) string {
+	if g.pkg.Path() == path {
+		return ""
+	}
+	g.imports[path] = name
+	return name + "."
+}
+
+func (g *generator) typeString(t types.Type) string {
+	return types.TypeString(t, func(p *types.Package) string {
+		if p == g.pkg {
+			return ""
+		}
+		g.imports[p.Path()] = p.Name()
+		return p.Name()
+	})
+}
+
+func (g *generator) xjson(name string) string {
+	return g.use(xjsonPath, "xjson") + name
+}
+
+func (g *generator) decoderType(t types.Type) string {
+	return g.xjson("Decoder[" + g.typeString(t) + "]")
+}
+
+func (g *generator) std(t types.Type) string {
+	return g.xjson("Std[" + g.typeString(t) + "]()")
+}
+
+// isNamed reports whether t is named type name of package with path.
+func isNamed(t types.Type, pkgPath, name string) bool {
+	named, ok := types.Unalias(t).(*types.Named)
+	return ok &&
+		named.Obj().Pkg() != nil &&
+		named.Obj().Pkg().Path() == pkgPath &&
+		named.Obj().Name() == name
+}
+
+// optionElem returns T if t is fun.Option[T].
+func optionElem(t types.Type) (types.Type, bool) {
+	if !isNamed(t, funPath, "Option") {
+		return nil, false
+	}
+	return types.Unalias(t).(*types.Named).TypeArgs().At(0), true
+}
+
+// isUnmarshaler reports whether t decodes itself, so combinators cannot be used.
+func isUnmarshaler(t types.Type) bool {
+	for _, method := range []string{"UnmarshalJSON", "UnmarshalText"} {
+		if obj, _, _ := types.LookupFieldOrMethod(types.NewPointer(t), true, nil, method); obj != nil {
+			return true
+		}
+	}
+	return false
+}
+
+var basicDecoders = map[types.BasicKind]string{
+	types.Bool:    "Bool",
+	types.String:  "String",
+	types.Int:     "Int",
+	types.Int8:    "Int8",
+	types.Int16:   "Int16",
+	types.Int32:   "Int32",
+	types.Int64:   "Int64",
+	types.Uint:    "Uint",
+	types.Uint8:   "Uint8",
+	types.Uint16:  "Uint16",
+	types.Uint32:  "Uint32",
+	types.Uint64:  "Uint64",
+	types.Float64: "Float",
+}
+
+// convert makes decoder of t from decoder of its underlying type u, if they differ.
+func (g *generator) convert(t, u types.Type, decoder string) string {
+	if types.Identical(t, u) {
+		return decoder
+	}
+	return fmt.Sprintf("%s(func(v %s) %s { return %s(v) }, %s)",
+		g.xjson("Map"), g.typeString(u), g.typeString(t), g.typeString(t), decoder)
+}
+
+// decoder returns expression of decoder of value of type t.
+func (g *generator) decoder(t types.Type) (string, error) {
+	switch {
+	case isNamed(t, "time", "Time"):
+		return g.xjson("Time"), nil
+	case isNamed(t, "encoding/json", "Number"):
+		return g.xjson("Number"), nil
+	}
+	if elem, ok := optionElem(t); ok {
+		d, err := g.decoder(elem)
+		if err != nil {
+			return "", err
+		}
+		return g.xjson("Nullable(" + d + ")"), nil
+	}
+	if ptr, ok := t.(*types.Pointer); ok {
+		switch {
+		case isNamed(ptr.Elem(), "math/big", "Int"):
+			return g.xjson("BigInt"), nil
+		case isNamed(ptr.Elem(), "math/big", "Float"):
+			return g.xjson("BigFloat"), nil
+		}
+	}
+	if isUnmarshaler(t) {
+		return g.std(t), nil
+	}
+
+	switch u := t.Underlying().(type) {
+	case *types.Basic:
+		if u.Kind() == types.Invalid {
+			return "", errors.New("invalid type")
+		}
+		if u.Kind() == types.Float32 {
+			return fmt.Sprintf("%s(func(v float64) %s { return %s(v) }, %s)",
+				g.xjson("Map"), g.typeString(t), g.typeString(t), g.xjson("Float")), nil
+		}
+
+		name, ok := basicDecoders[u.Kind()]
+		if !ok {
+			return g.std(t), nil
+		}
+		return g.convert(t, u, g.xjson(name)), nil
+	case *types.Pointer:
+		if !types.Identical(t, u) {
+			return g.std(t), nil
+		}
+
+		d, err := g.decoder(u.Elem())
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("%s(%sOption[%s].Ptr, %s)",
+			g.xjson("Map"), g.use(funPath, "fun"), g.typeString(u.Elem()), g.xjson("Nullable("+d+")")), nil
+	case *types.Slice:
+		if basic, ok := u.Elem().Underlying().(*types.Basic); ok && basic.Kind() == types.Byte {
+			// base64 encoded
+			return g.std(t), nil
+		}
+
+		d, err := g.decoder(u.Elem())
+		if err != nil {
+			return "", err
+		}
+		return g.convert(t, u, g.xjson("List("+d+")")), nil
+	case *types.Map:
+		if !types.Identical(u.Key(), types.Typ[types.String]) {
+			return g.std(t), nil
+		}
+
+		d, err := g.decoder(u.Elem())
+		if err != nil {
+			return "", err
+		}
+		return g.convert(t, u, g.xjson("Dict("+d+")")), nil
+	case *types.Struct:
+		named, ok := types.Unalias(t).(*types.Named)
+		switch {
+		case !ok:
+			return g.object(t, u)
+		case named.Obj().Pkg() == g.pkg && named.TypeArgs().Len() == 0:
+			g.enqueue(named)
+			// lazy, since types might be recursive
+			return fmt.Sprintf("%s(func() %s { return %s })",
+				g.xjson("Lazy"), g.decoderType(named), decoderName(named)), nil
+		default:
+			return g.std(t), nil
+		}
+	case *types.Interface:
+		if u.Empty() && types.Identical(t, types.Universe.Lookup("any").Type()) {
+			return g.xjson("Any"), nil
+		}
+		return g.std(t), nil
+	default:
+		return g.std(t), nil
+	}
+}
+
+// zero returns expression of zero value of type t.
+func (g *generator) zero(t types.Type) string {
+	switch u := t.Underlying().(type) {
+	case *types.Basic:
+		switch {
+		case u.Info()&types.IsBoolean != 0:
+			return "false"
+		case u.Info()&types.IsString != 0:
+			return `""`
+		default:
+			return "0"
+		}
+	case *types.Pointer, *types.Slice, *types.Map, *types.Interface, *types.Chan, *types.Signature:
+		return "nil"
+	default:
+		return g.typeString(t) + "{}"
+	}
+}
+
+// fields lists JSON fields of struct, as encoding/json does. Fields of embedded structs
+// are promoted, unless shadowed by fields of outer struct.
+func fields(st *types.Struct, prefix string, seen map[string]bool) ([]field, error) {
+	var res []field
+	var embedded []*types.Var
+	for i := range st.NumFields() {
+		f := st.Field(i)
+		tag, _ := reflect.StructTag(st.Tag(i)).Lookup("json")
+		if tag == "-" {
+			continue
+		}
+
+		name, opts, _ := strings.Cut(tag, ",")
+		if f.Embedded() && name == "" {
+			if _, ok := f.Type().Underlying().(*types.Struct); ok {
+				embedded = append(embedded, f)
+				continue
+			}
+			if _, ok := f.Type().Underlying().(*types.Pointer); ok {
+				return nil, fmt.Errorf("embedded pointer %s is not supported", f.Name())
+			}
+		}
+		if !f.Exported() {
+			continue
+		}
+
+		if name == "" {
+			name = f.Name()
+		}
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+
+		optList := strings.Split(opts, ",")
+		res = append(res, field{
+			name:      name,
+			path:      prefix + f.Name(),
+			typ:       f.Type(),
+			omitEmpty: slices.Contains(optList, "omitempty"),
+			asString:  slices.Contains(optList, "string"),
+		})
+	}
+
+	for _, f := range embedded {
+		promoted, err := fields(f.Type().Underlying().(*types.Struct), prefix+f.Name()+".", seen)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, promoted...)
+	}
+	return res, nil
+}
+
+// object returns expression of decoder of struct type t.
+func (g *generator) object(t types.Type, st *types.Struct) (string, error) {
+	fs, err := fields(st, "", map[string]bool{})
+	if err != nil {
+		return "", err
+	}
+
+	owner := g.typeString(t)
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s[%s]().Field(\n", g.xjson("Object"), owner)
+	for _, f := range fs {
+		expr, err := g.field(owner, f)
+		if err != nil {
+			return "", fmt.Errorf("field %s: %w", f.path, err)
+		}
+		b.WriteString(expr + ",\n")
+	}
+	b.WriteString(").Decoder()")
+	return b.String(), nil
+}
+
+// field returns expression of object field decoder.
+func (g *generator) field(owner string, f field) (string, error) {
+	name := strconv.Quote(f.name)
+	set := fmt.Sprintf("func(x *%s, v %s) { x.%s = v }", owner, g.typeString(f.typ), f.path)
+
+	if elem, ok := optionElem(f.typ); ok {
+		// xjson.Option makes both missing field and null invalid option, regardless of omitempty
+		d, err := g.decoder(elem)
+		if err != nil {
+			return "", err
+		}
+
+		return fmt.Sprintf("%s(%s(%s, %s), %s)", g.xjson("Assign"), g.xjson("Option"), name, d, set), nil
+	}
+
+	var d string
+	if f.asString {
+		switch {
+		case types.Identical(f.typ, types.Typ[types.Int]):
+			d = g.xjson("IntString")
+		case types.Identical(f.typ, types.Typ[types.Float64]):
+			d = g.xjson("FloatString")
+		default:
+			return "", fmt.Errorf("string option is not supported for %s", g.typeString(f.typ))
+		}
+	} else {
+		var err error
+		if d, err = g.decoder(f.typ); err != nil {
+			return "", err
+		}
+	}
+
+	if f.omitEmpty {
+		return fmt.Sprintf("%s(%s, %s, %s, %s)", g.xjson("Optional"), name, d, g.zero(f.typ), set), nil
+	}
+	return fmt.Sprintf("%s(%s, %s, %s)", g.xjson("Required"), name, d, set), nil
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rprtr258/assert"
+	"github.com/rprtr258/fun"
+	"github.com/rprtr258/fun/cmd/jsondecgen/testdata/basic"
+)
+
+var update = flag.Bool("update", false, "update golden files")
+
+func TestGolden(t *testing.T) {
+	for _, test := range []struct {
+		dir    string
+		types  []string
+		golden string
+	}{
+		{"testdata/basic", []string{"User"}, "user_jsondec.go"},
+	} {
+		t.Run(test.dir, func(t *testing.T) {
+			got, err := generate(test.dir, test.types, test.golden)
+			assert.NoError(t, err)
+
+			golden := filepath.Join(test.dir, test.golden)
+			if *update {
+				assert.NoError(t, os.WriteFile(golden, got, 0o644))
+			}
+
+			expected, err := os.ReadFile(golden)
+			assert.NoError(t, err)
+			assert.Equal(t, string(expected), string(got))
+		})
+	}
+}
+
+func TestGenerated(t *testing.T) {
+	t.Parallel()
+
+	user, err := basic.ParseUser(`{
+		"id": 1,
+		"created": "2024-01-02T03:04:05Z",
+		"name": "Sam",
+		"email": "sam@example.com",
+		"age": null,
+		"status": "active",
+		"balance": 123456789012345678901234567890,
+		"visits": "9007199254740993",
+		"address": {"city": "Paris", "geo": {"Lat": 48.85, "Lon": 2.35}},
+		"manager": {"id": 2, "created": "2024-01-02T03:04:05Z", "name": "Bob", "age": 40, "status": "away", "visits": "0", "address": {"city": "Lyon"}, "email": null, "manager": null, "Verified": false},
+		"Verified": true,
+		"Password": "secret"
+	}`)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, user.ID)
+	assert.Equal(t, "Sam", user.Name)
+	assert.Equal(t, fun.Valid("sam@example.com"), user.Email)
+	assert.Equal(t, (*int)(nil), user.Age)
+	assert.Equal(t, basic.Status("active"), user.Status)
+	assert.Equal(t, "123456789012345678901234567890", user.Balance.String())
+	assert.Equal(t, 9007199254740993, user.Visits)
+	assert.Equal(t, 48.85, user.Address.Geo.Lat)
+	assert.Equal(t, "Bob", user.Manager.Name)
+	assert.Equal(t, 40, *user.Manager.Age)
+	assert.Equal(t, fun.Invalid[string](), user.Manager.Email)
+	assert.Equal(t, "", user.Password)
+	assert.True(t, user.Verified)
+
+	_, err = basic.ParseUser(`{"id": 1, "name": "Sam", "age": 1, "status": "", "visits": 3}`)
+	assert.EqualError(t, "/visits: expected string, got number", err)
+}
+
+func TestErrors(t *testing.T) {
+	t.Parallel()
+
+	_, err := generate("testdata/basic", []string{"Missing"}, "")
+	assert.EqualError(t, "type Missing not found", err)
+
+	_, err = generate("testdata/basic", []string{"Status"}, "")
+	assert.EqualError(t, "type Status is not a struct", err)
+
+	_, err = generate("testdata/typo", []string{"T"}, "")
+	assert.EqualError(t, "testdata/typo/types.go:4:4: undefined: Strnig", err)
+
+	// errors of files using generated decoders are ignored, but broken fields are not
+	_, err = generate("testdata/typoref", []string{"T"}, "")
+	assert.EqualError(t, "type T: field A: invalid type", err)
+}
@@ -0,0 +1,59 @@
+// Jsondecgen generates exp/json decoders for struct types, following their json tags.
+//
+// Usage:
+//
+//	//go:generate go run github.com/rprtr258/fun/cmd/jsondecgen -type User,Account
+//
+// For every type T variable decoderT of type xjson.Decoder[T] is generated into
+// <type>_jsondec.go, where <type> is the first type name lowercased. Fields without
+// omitempty are required, fields with it fall back to zero value. fun.Option fields are
+// decoded by xjson.Option, so they are invalid if missing or null. Pointers are decoded
+// as nullable values. Struct types of the same
+// package used by fields get their decoders generated too. Types which cannot be
+// expressed by combinators, e.g. json.Unmarshaler implementations, are decoded by
+// xjson.Std.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+func main() {
+	typeNames := flag.String("type", "", "comma-separated list of type names, required")
+	output := flag.String("output", "", "output file name, default <type>_jsondec.go")
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: jsondecgen -type T [-output file] [directory]\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if *typeNames == "" || flag.NArg() > 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	dir := "."
+	if flag.NArg() == 1 {
+		dir = flag.Arg(0)
+	}
+
+	types := strings.Split(*typeNames, ",")
+	if *output == "" {
+		*output = filepath.Join(dir, strings.ToLower(types[0])+"_jsondec.go")
+	}
+
+	src, err := generate(dir, types, filepath.Base(*output))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "jsondecgen:", err)
+		os.Exit(1)
+	}
+
+	if err := os.WriteFile(*output, src, 0o644); err != nil {
+		fmt.Fprintln(os.Stderr, "jsondecgen:", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,50 @@
+package basic
+
+//go:generate go run ../.. -type User
+
+import (
+	"encoding/json"
+	"math/big"
+	"time"
+
+	"github.com/rprtr258/fun"
+)
+
+type Status string
+
+type Base struct {
+	ID      int       `json:"id"`
+	Created time.Time `json:"created"`
+}
+
+type Address struct {
+	City string `json:"city"`
+	Geo  struct {
+		Lat, Lon float64
+	} `json:"geo,omitempty"`
+}
+
+type User struct {
+	Base
+	Name     string             `json:"name"`
+	Email    fun.Option[string] `json:"email"`
+	Age      *int               `json:"age"`
+	Tags     []string           `json:"tags,omitempty"`
+	Scores   map[string]float64 `json:"scores,omitempty"`
+	Status   Status             `json:"status"`
+	Balance  *big.Int           `json:"balance,omitempty"`
+	Visits   int                `json:"visits,string"`
+	Ratio    float32            `json:"ratio,omitempty"`
+	Address  Address            `json:"address"`
+	Manager  *User              `json:"manager"`
+	Extra    any                `json:"extra,omitempty"`
+	Raw      json.RawMessage    `json:"raw,omitempty"`
+	Password string             `json:"-"`
+	Verified bool
+	internal int
+}
+
+// ParseUser uses generated decoder.
+func ParseUser(s string) (User, error) {
+	return decoderUser.ParseString(s)
+}
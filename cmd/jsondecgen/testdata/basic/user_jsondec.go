@@ -0,0 +1,66 @@
+// Code generated by "jsondecgen -type User"; DO NOT EDIT.
+
+package basic
+
+import (
+	"encoding/json"
+	"math/big"
+	"time"
+
+	"github.com/rprtr258/fun"
+	xjson "github.com/rprtr258/fun/exp/json"
+)
+
+var (
+	decoderUser    xjson.Decoder[User]
+	decoderAddress xjson.Decoder[Address]
+)
+
+func init() {
+	decoderUser = xjson.Object[User]().Field(
+		xjson.Required("name", xjson.String, func(x *User, v string) { x.Name = v }),
+		xjson.Assign(xjson.Option("email", xjson.String), func(x *User, v fun.Option[string]) { x.Email = v }),
+		xjson.Required("age", xjson.Map(fun.Option[int].Ptr, xjson.Nullable(xjson.Int)), func(x *User, v *int) { x.Age = v }),
+		xjson.Optional("tags", xjson.List(xjson.String), nil, func(x *User, v []string) { x.Tags = v }),
+		xjson.Optional("scores", xjson.Dict(xjson.Float), nil, func(x *User, v map[string]float64) { x.Scores = v }),
+		xjson.Required("status", xjson.Map(func(v string) Status { return Status(v) }, xjson.String), func(x *User, v Status) { x.Status = v }),
+		xjson.Optional("balance", xjson.BigInt, nil, func(x *User, v *big.Int) { x.Balance = v }),
+		xjson.Required("visits", xjson.IntString, func(x *User, v int) { x.Visits = v }),
+		xjson.Optional("ratio", xjson.Map(func(v float64) float32 { return float32(v) }, xjson.Float), 0, func(x *User, v float32) { x.Ratio = v }),
+		xjson.Required("address", xjson.Lazy(func() xjson.Decoder[Address] { return decoderAddress }), func(x *User, v Address) { x.Address = v }),
+		xjson.Required("manager", xjson.Map(fun.Option[User].Ptr, xjson.Nullable(xjson.Lazy(func() xjson.Decoder[User] { return decoderUser }))), func(x *User, v *User) { x.Manager = v }),
+		xjson.Optional("extra", xjson.Any, nil, func(x *User, v any) { x.Extra = v }),
+		xjson.Optional("raw", xjson.Std[json.RawMessage](), nil, func(x *User, v json.RawMessage) { x.Raw = v }),
+		xjson.Required("Verified", xjson.Bool, func(x *User, v bool) { x.Verified = v }),
+		xjson.Required("id", xjson.Int, func(x *User, v int) { x.Base.ID = v }),
+		xjson.Required("created", xjson.Time, func(x *User, v time.Time) { x.Base.Created = v }),
+	).Decoder()
+	decoderAddress = xjson.Object[Address]().Field(
+		xjson.Required("city", xjson.String, func(x *Address, v string) { x.City = v }),
+		xjson.Optional("geo", xjson.Object[struct {
+			Lat float64
+			Lon float64
+		}]().Field(
+			xjson.Required("Lat", xjson.Float, func(x *struct {
+				Lat float64
+				Lon float64
+			}, v float64) {
+				x.Lat = v
+			}),
+			xjson.Required("Lon", xjson.Float, func(x *struct {
+				Lat float64
+				Lon float64
+			}, v float64) {
+				x.Lon = v
+			}),
+		).Decoder(), struct {
+			Lat float64
+			Lon float64
+		}{}, func(x *Address, v struct {
+			Lat float64
+			Lon float64
+		}) {
+			x.Geo = v
+		}),
+	).Decoder()
+}
@@ -0,0 +1,5 @@
+package typo
+
+type T struct {
+	A Strnig
+}
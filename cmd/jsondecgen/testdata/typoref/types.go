@@ -0,0 +1,7 @@
+package typoref
+
+type T struct {
+	A Strnig
+}
+
+func ParseT(s string) (T, error) { return decoderT.ParseString(s) }
@@ -0,0 +1,105 @@
+package fun
+
+// EditKind is a kind of edit script operation.
+type EditKind int
+
+const (
+	// EditEqual keeps element present in both sequences.
+	EditEqual EditKind = iota
+	// EditDelete removes element of the first sequence.
+	EditDelete
+	// EditInsert adds element of the second sequence.
+	EditInsert
+)
+
+func (k EditKind) String() string {
+	switch k {
+	case EditEqual:
+		return "="
+	case EditDelete:
+		return "-"
+	case EditInsert:
+		return "+"
+	default:
+		return "?"
+	}
+}
+
+// Edit is an edit script operation.
+// A and B are indices of the element in first and second sequences respectively.
+// For deleted element B is the number of second sequence elements preceding it,
+// for inserted element A is the number of first sequence elements preceding it.
+type Edit[T any] struct {
+	Kind  EditKind
+	A, B  int
+	Value T
+}
+
+// Diff returns the shortest edit script transforming a into b, using Myers' algorithm.
+// Deletions are preferred to come before insertions in changed regions.
+func Diff[T comparable](a, b []T) []Edit[T] {
+	n, m := len(a), len(b)
+	maxD := n + m
+	offset := maxD + 1
+	v := make([]int, 2*maxD+3)
+	// trace[d] keeps diagonals -d-1..d+1 of v before step d, the only ones step d reads
+	var trace [][]int
+
+	// forward pass: find furthest reaching paths for increasing number of edits
+	found := false
+	for d := 0; d <= maxD && !found; d++ {
+		trace = append(trace, append([]int(nil), v[offset-d-1:offset+d+2]...))
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || k != d && v[offset+k-1] < v[offset+k+1] {
+				x = v[offset+k+1] // down: insertion
+			} else {
+				x = v[offset+k-1] + 1 // right: deletion
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			v[offset+k] = x
+			if x >= n && y >= m {
+				found = true
+				break
+			}
+		}
+	}
+
+	// backward pass: restore path from traces
+	res := make([]Edit[T], 0, max(n, m))
+	x, y := n, m
+	for d := len(trace) - 1; d >= 0; d-- {
+		v, offset := trace[d], d+1
+		k := x - y
+		var prevK int
+		if k == -d || k != d && v[offset+k-1] < v[offset+k+1] {
+			prevK = k + 1
+		} else {
+			prevK = k - 1
+		}
+		prevX := v[offset+prevK]
+		prevY := prevX - prevK
+		for x > prevX && y > prevY {
+			x--
+			y--
+			res = append(res, Edit[T]{EditEqual, x, y, a[x]})
+		}
+		if d == 0 {
+			break
+		}
+
+		if x == prevX {
+			y--
+			res = append(res, Edit[T]{EditInsert, x, y, b[y]})
+		} else {
+			x--
+			res = append(res, Edit[T]{EditDelete, x, y, a[x]})
+		}
+	}
+	ReverseInplace(res)
+	return res
+}
@@ -0,0 +1,65 @@
+package fun
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/rprtr258/assert"
+)
+
+// applyEdits reconstructs both sequences from edit script.
+func applyEdits[T any](edits []Edit[T]) (a, b []T) {
+	for _, e := range edits {
+		if e.Kind != EditInsert {
+			a = append(a, e.Value)
+		}
+		if e.Kind != EditDelete {
+			b = append(b, e.Value)
+		}
+	}
+	return a, b
+}
+
+func TestDiff(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		a, b    string
+		changes int
+	}{
+		{"", "", 0},
+		{"abc", "abc", 0},
+		{"", "abc", 3},
+		{"abc", "", 3},
+		{"abcabba", "cbabac", 5},
+		{"kitten", "sitting", 5},
+		{strings.Repeat("ab", 50), strings.Repeat("ba", 50), 2},
+	} {
+		a, b := strings.Split(test.a, ""), strings.Split(test.b, "")
+		edits := Diff(a, b)
+
+		gotA, gotB := applyEdits(edits)
+		assert.Equal(t, test.a, strings.Join(gotA, ""))
+		assert.Equal(t, test.b, strings.Join(gotB, ""))
+
+		changes := 0
+		for _, e := range edits {
+			if e.Kind != EditEqual {
+				changes++
+			}
+		}
+		assert.Equal(t, test.changes, changes)
+	}
+}
+
+func TestDiffIndices(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, []Edit[int]{
+		{EditEqual, 0, 0, 1},
+		{EditDelete, 1, 1, 2},
+		{EditInsert, 2, 1, 4},
+		{EditEqual, 2, 2, 3},
+		{EditInsert, 3, 3, 5},
+	}, Diff([]int{1, 2, 3}, []int{1, 4, 3, 5}))
+}
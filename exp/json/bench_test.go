@@ -0,0 +1,62 @@
+package json
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+type benchUser struct {
+	ID    int      `json:"id"`
+	Name  string   `json:"name"`
+	Email string   `json:"email"`
+	Tags  []string `json:"tags"`
+}
+
+var decoderBenchUser = Map4(
+	func(id int, name, email string, tags []string) benchUser {
+		return benchUser{id, name, email, tags}
+	},
+	Int.Field("id"),
+	String.Field("name"),
+	String.Field("email"),
+	List(String).Field("tags"),
+)
+
+func benchDocument(n int) []byte {
+	var sb strings.Builder
+	sb.WriteString("[")
+	for i := range n {
+		if i > 0 {
+			sb.WriteString(",")
+		}
+		fmt.Fprintf(&sb, `{"id":%d,"name":"user %d","email":"user%d@example.com","tags":["a","b","c"]}`, i, i, i)
+	}
+	sb.WriteString("]")
+	return []byte(sb.String())
+}
+
+func BenchmarkDecoder(b *testing.B) {
+	doc := benchDocument(1000)
+	decoder := List(decoderBenchUser)
+	b.SetBytes(int64(len(doc)))
+	b.ReportAllocs()
+	for range b.N {
+		if _, err := decoder.ParseBytes(doc); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkUnmarshal(b *testing.B) {
+	doc := benchDocument(1000)
+	b.SetBytes(int64(len(doc)))
+	b.ReportAllocs()
+	for range b.N {
+		var users []benchUser
+		if err := json.Unmarshal(doc, &users); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
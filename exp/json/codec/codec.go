@@ -0,0 +1,117 @@
+// Codec pairs decoder and encoder, so single definition describes both directions.
+// See for inspiration
+// https://package.elm-lang.org/packages/miniBill/elm-codec/latest/Codec
+package codec
+
+import (
+	"time"
+
+	"github.com/rprtr258/fun"
+	"github.com/rprtr258/fun/exp/json"
+	"github.com/rprtr258/fun/exp/json/encode"
+)
+
+type Codec[T any] struct {
+	Decoder json.Decoder[T]
+	Encoder encode.Encoder[T]
+}
+
+func (c Codec[T]) ParseBytes(b []byte) (T, error) {
+	return c.Decoder.ParseBytes(b)
+}
+
+func (c Codec[T]) ParseString(s string) (T, error) {
+	return c.Decoder.ParseString(s)
+}
+
+func (c Codec[T]) ToBytes(t T) ([]byte, error) {
+	return c.Encoder.ToBytes(t)
+}
+
+func (c Codec[T]) ToString(t T) (string, error) {
+	return c.Encoder.ToString(t)
+}
+
+var (
+	Int    = Codec[int]{json.Int, encode.Int}
+	String = Codec[string]{json.String, encode.String}
+	Bool   = Codec[bool]{json.Bool, encode.Bool}
+	Float  = Codec[float64]{json.Float, encode.Float}
+	Time   = Codec[time.Time]{json.Time, encode.Time}
+	Any    = Codec[any]{json.Any, encode.Any}
+)
+
+// Std encodes and decodes value using encoding/json.
+func Std[T any]() Codec[T] {
+	return Codec[T]{json.Std[T](), encode.Std[T]()}
+}
+
+// Map converts codec using pair of inverse functions.
+func Map[A, B any](to func(A) B, from func(B) A, c Codec[A]) Codec[B] {
+	return Codec[B]{json.Map(to, c.Decoder), encode.Map(from, c.Encoder)}
+}
+
+func Nullable[T any](c Codec[T]) Codec[fun.Option[T]] {
+	return Codec[fun.Option[T]]{json.Nullable(c.Decoder), encode.Nullable(c.Encoder)}
+}
+
+func List[T any](c Codec[T]) Codec[[]T] {
+	return Codec[[]T]{json.List(c.Decoder), encode.List(c.Encoder)}
+}
+
+func Dict[T any](c Codec[T]) Codec[map[string]T] {
+	return Codec[map[string]T]{json.Dict(c.Decoder), encode.Dict(c.Encoder)}
+}
+
+// Field describes single field of object codec.
+type Field[T any] struct {
+	decoder json.ObjectField[T]
+	encoder encode.Field[T]
+}
+
+// Required describes field which must be present in object.
+func Required[T, F any](name string, get func(T) F, set func(*T, F), c Codec[F]) Field[T] {
+	return Field[T]{
+		decoder: json.Required(name, c.Decoder, set),
+		encoder: encode.Required(name, get, c.Encoder),
+	}
+}
+
+// Option describes field which might be absent in object, null is decoded as absent too.
+func Option[T, F any](name string, get func(T) fun.Option[F], set func(*T, fun.Option[F]), c Codec[F]) Field[T] {
+	return Field[T]{
+		decoder: json.Assign(json.Option(name, c.Decoder), set),
+		encoder: encode.Option(name, get, c.Encoder),
+	}
+}
+
+// Object makes codec of object with given fields, decoding errors of all fields are accumulated.
+func Object[T any](fields ...Field[T]) Codec[T] {
+	decoders := make([]json.ObjectField[T], len(fields))
+	encoders := make([]encode.Field[T], len(fields))
+	for i, field := range fields {
+		decoders[i] = field.decoder
+		encoders[i] = field.encoder
+	}
+
+	return Codec[T]{
+		Decoder: json.Object[T]().Field(decoders...).Decoder(),
+		Encoder: encode.Object(encoders...),
+	}
+}
+
+// Tagged describes discriminated union, variant is chosen by tag of value when encoding
+// and by value of string field when decoding.
+func Tagged[T any](field string, tag func(T) string, variants map[string]Codec[T]) Codec[T] {
+	decoders := make(map[string]json.Decoder[T], len(variants))
+	encoders := make(map[string]encode.Encoder[T], len(variants))
+	for name, c := range variants {
+		decoders[name] = c.Decoder
+		encoders[name] = c.Encoder
+	}
+
+	return Codec[T]{
+		Decoder: json.Tagged(field, decoders),
+		Encoder: encode.Tagged(field, tag, encoders),
+	}
+}
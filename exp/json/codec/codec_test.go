@@ -0,0 +1,171 @@
+package codec
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"testing"
+	"time"
+
+	"github.com/rprtr258/assert"
+	"github.com/rprtr258/fun"
+)
+
+type User struct {
+	ID      int
+	Name    string
+	Email   fun.Option[string]
+	Age     fun.Option[int]
+	Tags    []string
+	Scores  map[string]float64
+	Created time.Time
+}
+
+var codecUser = Object(
+	Required("id", func(u User) int { return u.ID }, func(u *User, id int) { u.ID = id }, Int),
+	Required("name", func(u User) string { return u.Name }, func(u *User, name string) { u.Name = name }, String),
+	Option("email", func(u User) fun.Option[string] { return u.Email }, func(u *User, email fun.Option[string]) { u.Email = email }, String),
+	Required("age", func(u User) fun.Option[int] { return u.Age }, func(u *User, age fun.Option[int]) { u.Age = age }, Nullable(Int)),
+	Required("tags", func(u User) []string { return u.Tags }, func(u *User, tags []string) { u.Tags = tags }, List(String)),
+	Required("scores", func(u User) map[string]float64 { return u.Scores }, func(u *User, scores map[string]float64) { u.Scores = scores }, Dict(Float)),
+	Required("created", func(u User) time.Time { return u.Created }, func(u *User, created time.Time) { u.Created = created }, Time),
+)
+
+func randomString(rng *rand.Rand) string {
+	const alphabet = "abc ~/\"\\\n\tжё😀"
+	runes := []rune(alphabet)
+	res := make([]rune, rng.IntN(8))
+	for i := range res {
+		res[i] = runes[rng.IntN(len(runes))]
+	}
+	return string(res)
+}
+
+func randomOption[T any](rng *rand.Rand, gen func(*rand.Rand) T) fun.Option[T] {
+	if rng.IntN(2) == 0 {
+		return fun.Invalid[T]()
+	}
+	return fun.Valid(gen(rng))
+}
+
+func randomUser(rng *rand.Rand) User {
+	var tags []string
+	if n := rng.IntN(4); n > 0 {
+		tags = make([]string, n)
+		for i := range tags {
+			tags[i] = randomString(rng)
+		}
+	}
+
+	scores := map[string]float64{}
+	for range rng.IntN(4) {
+		scores[randomString(rng)] = rng.NormFloat64() * 1e6
+	}
+
+	return User{
+		ID:      rng.IntN(1<<53) - 1<<52,
+		Name:    randomString(rng),
+		Email:   randomOption(rng, randomString),
+		Age:     randomOption(rng, func(rng *rand.Rand) int { return rng.IntN(150) }),
+		Tags:    tags,
+		Scores:  scores,
+		Created: time.Unix(rng.Int64N(1<<33), rng.Int64N(1e9)).UTC(),
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	rng := rand.New(rand.NewPCG(1, 2))
+	for range 1000 {
+		user := randomUser(rng)
+
+		b, err := codecUser.ToBytes(user)
+		assert.NoError(t, err)
+
+		got, err := codecUser.ParseBytes(b)
+		assert.NoError(t, err)
+		assert.Equal(t, user, got)
+	}
+}
+
+func TestMap(t *testing.T) {
+	t.Parallel()
+
+	type ID struct{ Value int }
+	codecID := Map(func(i int) ID { return ID{i} }, func(id ID) int { return id.Value }, Int)
+
+	s, err := List(codecID).ToString([]ID{{1}, {2}})
+	assert.NoError(t, err)
+	assert.Equal(t, `[1,2]`, s)
+
+	ids, err := List(codecID).ParseString(s)
+	assert.NoError(t, err)
+	assert.Equal(t, []ID{{1}, {2}}, ids)
+}
+
+func TestObjectErrors(t *testing.T) {
+	t.Parallel()
+
+	_, err := codecUser.Decoder.ParseStringAll(`{"id": "1", "name": 2, "age": null, "tags": [], "scores": {}}`)
+	assert.EqualError(t, "/id: expected number, got string; /name: expected string, got number; /created: required field is missing", err)
+}
+
+type Shape interface{ isShape() }
+
+type Circle struct{ Radius float64 }
+
+type Rect struct{ Width, Height float64 }
+
+func (Circle) isShape() {}
+func (Rect) isShape()   {}
+
+var codecShape = Tagged("type",
+	func(s Shape) string {
+		switch s.(type) {
+		case Circle:
+			return "circle"
+		case Rect:
+			return "rect"
+		default:
+			return fmt.Sprintf("%T", s)
+		}
+	},
+	map[string]Codec[Shape]{
+		"circle": Map(
+			func(c Circle) Shape { return c },
+			func(s Shape) Circle { return s.(Circle) },
+			Object(
+				Required("radius", func(c Circle) float64 { return c.Radius }, func(c *Circle, r float64) { c.Radius = r }, Float),
+			),
+		),
+		"rect": Map(
+			func(r Rect) Shape { return r },
+			func(s Shape) Rect { return s.(Rect) },
+			Object(
+				Required("width", func(r Rect) float64 { return r.Width }, func(r *Rect, w float64) { r.Width = w }, Float),
+				Required("height", func(r Rect) float64 { return r.Height }, func(r *Rect, h float64) { r.Height = h }, Float),
+			),
+		),
+	},
+)
+
+type Square struct{}
+
+func (Square) isShape() {}
+
+func TestTagged(t *testing.T) {
+	t.Parallel()
+
+	shapes := []Shape{Circle{1}, Rect{2, 3}}
+
+	s, err := List(codecShape).ToString(shapes)
+	assert.NoError(t, err)
+	assert.Equal(t, `[{"radius":1,"type":"circle"},{"height":3,"type":"rect","width":2}]`, s)
+
+	got, err := List(codecShape).ParseString(s)
+	assert.NoError(t, err)
+	assert.Equal(t, shapes, got)
+
+	_, err = codecShape.ToString(Square{})
+	assert.EqualError(t, `/type: unknown type "codec.Square"`, err)
+}
@@ -0,0 +1,150 @@
+package json
+
+import (
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"unicode/utf8"
+)
+
+// Constraint is a named check of decoded value, described in schema by Keywords,
+// unlike arbitrary check of Validate.
+type Constraint[T any] struct {
+	Keywords map[string]any
+	Check    func(T) error
+}
+
+type number interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 |
+		~float32 | ~float64
+}
+
+func Minimum[T number](minimum T) Constraint[T] {
+	return Constraint[T]{
+		Keywords: map[string]any{"minimum": minimum},
+		Check: func(x T) error {
+			if x < minimum {
+				return fmt.Errorf("must be at least %v, got %v", minimum, x)
+			}
+			return nil
+		},
+	}
+}
+
+func Maximum[T number](maximum T) Constraint[T] {
+	return Constraint[T]{
+		Keywords: map[string]any{"maximum": maximum},
+		Check: func(x T) error {
+			if x > maximum {
+				return fmt.Errorf("must be at most %v, got %v", maximum, x)
+			}
+			return nil
+		},
+	}
+}
+
+// MinLength checks number of characters in string.
+func MinLength(n int) Constraint[string] {
+	return Constraint[string]{
+		Keywords: map[string]any{"minLength": n},
+		Check: func(s string) error {
+			if utf8.RuneCountInString(s) < n {
+				return fmt.Errorf("must be at least %d characters long", n)
+			}
+			return nil
+		},
+	}
+}
+
+// MaxLength checks number of characters in string.
+func MaxLength(n int) Constraint[string] {
+	return Constraint[string]{
+		Keywords: map[string]any{"maxLength": n},
+		Check: func(s string) error {
+			if utf8.RuneCountInString(s) > n {
+				return fmt.Errorf("must be at most %d characters long", n)
+			}
+			return nil
+		},
+	}
+}
+
+// Pattern checks that string matches regular expression, which should be in common
+// subset of RE2 and ECMA-262 syntaxes to be understood by schema consumers.
+func Pattern(pattern string) Constraint[string] {
+	re := regexp.MustCompile(pattern)
+	return Constraint[string]{
+		Keywords: map[string]any{"pattern": pattern},
+		Check: func(s string) error {
+			if !re.MatchString(s) {
+				return fmt.Errorf("must match %q", pattern)
+			}
+			return nil
+		},
+	}
+}
+
+func MinItems[T any](n int) Constraint[[]T] {
+	return Constraint[[]T]{
+		Keywords: map[string]any{"minItems": n},
+		Check: func(xs []T) error {
+			if len(xs) < n {
+				return fmt.Errorf("must have at least %d items", n)
+			}
+			return nil
+		},
+	}
+}
+
+func MaxItems[T any](n int) Constraint[[]T] {
+	return Constraint[[]T]{
+		Keywords: map[string]any{"maxItems": n},
+		Check: func(xs []T) error {
+			if len(xs) > n {
+				return fmt.Errorf("must have at most %d items", n)
+			}
+			return nil
+		},
+	}
+}
+
+// Enum checks that value is one of given ones.
+func Enum[T comparable](values ...T) Constraint[T] {
+	return Constraint[T]{
+		Keywords: map[string]any{"enum": values},
+		Check: func(x T) error {
+			if !slices.Contains(values, x) {
+				return fmt.Errorf("must be one of %v, got %v", values, x)
+			}
+			return nil
+		},
+	}
+}
+
+// Constrain checks decoded value with constraints, reporting all failed ones.
+func (d Decoder[T]) Constrain(constraints ...Constraint[T]) Decoder[T] {
+	return Decoder[T]{
+		decode: func(v value, res *T) error {
+			if err := d.decode(v, res); err != nil {
+				return err
+			}
+
+			var errs DecodeErrors
+			for _, c := range constraints {
+				if err := c.Check(*res); err != nil {
+					errs = append(errs, &DecodeError{Err: err})
+				}
+			}
+			return errs.err()
+		},
+		schema: func(defs *schemaDefs) map[string]any {
+			schema := maps.Clone(d.schema(defs))
+			for _, c := range constraints {
+				maps.Copy(schema, c.Keywords)
+			}
+			return schema
+		},
+	}
+}
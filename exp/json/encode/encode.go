@@ -0,0 +1,182 @@
+// See for inspiration
+// https://package.elm-lang.org/packages/elm/json/latest/Json-Encode
+package encode
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"time"
+
+	"github.com/rprtr258/fun"
+)
+
+// Encoder converts value to JSON value tree, made of nil, bool, numbers, string, []any and map[string]any.
+type Encoder[T any] func(T) (any, error)
+
+func (encoder Encoder[T]) ToBytes(t T) ([]byte, error) {
+	v, err := encoder(t)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(v)
+}
+
+func (encoder Encoder[T]) ToString(t T) (string, error) {
+	b, err := encoder.ToBytes(t)
+	return string(b), err
+}
+
+func primitiveEncoder[T any](t T) (any, error) {
+	return t, nil
+}
+
+var (
+	Int    Encoder[int]       = primitiveEncoder[int]
+	String Encoder[string]    = primitiveEncoder[string]
+	Bool   Encoder[bool]      = primitiveEncoder[bool]
+	Float  Encoder[float64]   = primitiveEncoder[float64]
+	Any    Encoder[any]       = primitiveEncoder[any]
+	Time   Encoder[time.Time] = func(t time.Time) (any, error) {
+		return t.Format(time.RFC3339Nano), nil
+	}
+)
+
+// Null encodes any value as null.
+func Null[T any](T) (any, error) {
+	return nil, nil
+}
+
+// Std encodes value using encoding/json.
+func Std[T any]() Encoder[T] {
+	return func(t T) (any, error) {
+		b, err := json.Marshal(t)
+		if err != nil {
+			return nil, err
+		}
+		return json.RawMessage(b), nil
+	}
+}
+
+// Map encodes value by converting it first.
+func Map[R, T any](f func(R) T, encoder Encoder[T]) Encoder[R] {
+	return func(r R) (any, error) {
+		return encoder(f(r))
+	}
+}
+
+// Nullable encodes invalid option as null.
+func Nullable[T any](encoder Encoder[T]) Encoder[fun.Option[T]] {
+	return func(o fun.Option[T]) (any, error) {
+		if !o.Valid {
+			return nil, nil
+		}
+		return encoder(o.Value)
+	}
+}
+
+// List encodes slice as array, nil slice is encoded as null.
+func List[T any](encoder Encoder[T]) Encoder[[]T] {
+	return func(ts []T) (any, error) {
+		if ts == nil {
+			return nil, nil
+		}
+
+		res := make([]any, len(ts))
+		for i, t := range ts {
+			v, err := encoder(t)
+			if err != nil {
+				return nil, withPath(err, indexSegment(i))
+			}
+			res[i] = v
+		}
+		return res, nil
+	}
+}
+
+// Dict encodes map as object.
+func Dict[T any](encoder Encoder[T]) Encoder[map[string]T] {
+	return func(m map[string]T) (any, error) {
+		res := make(map[string]any, len(m))
+		for k, t := range m {
+			v, err := encoder(t)
+			if err != nil {
+				return nil, withPath(err, k)
+			}
+			res[k] = v
+		}
+		return res, nil
+	}
+}
+
+// Field adds field of value to object being encoded.
+type Field[T any] func(T, map[string]any) error
+
+// Object encodes value as object made of given fields.
+func Object[T any](fields ...Field[T]) Encoder[T] {
+	return func(t T) (any, error) {
+		res := make(map[string]any, len(fields))
+		for _, field := range fields {
+			if err := field(t, res); err != nil {
+				return nil, err
+			}
+		}
+		return res, nil
+	}
+}
+
+// Required makes object field with given name from part of value.
+func Required[T, F any](name string, get func(T) F, encoder Encoder[F]) Field[T] {
+	return func(t T, res map[string]any) error {
+		v, err := encoder(get(t))
+		if err != nil {
+			return withPath(err, name)
+		}
+		res[name] = v
+		return nil
+	}
+}
+
+// Option makes object field with given name from optional part of value.
+// Field is omitted if option is invalid.
+func Option[T, F any](name string, get func(T) fun.Option[F], encoder Encoder[F]) Field[T] {
+	return func(t T, res map[string]any) error {
+		o := get(t)
+		if !o.Valid {
+			return nil
+		}
+
+		v, err := encoder(o.Value)
+		if err != nil {
+			return withPath(err, name)
+		}
+		res[name] = v
+		return nil
+	}
+}
+
+// Tagged encodes discriminated union, choosing variant encoder by tag of value and
+// adding tag as string field to encoded object.
+func Tagged[T any](field string, tag func(T) string, variants map[string]Encoder[T]) Encoder[T] {
+	return func(t T) (any, error) {
+		name := tag(t)
+		encoder, ok := variants[name]
+		if !ok {
+			return nil, withPath(fmt.Errorf("unknown type %q", name), field)
+		}
+
+		v, err := encoder(t)
+		if err != nil {
+			return nil, err
+		}
+
+		obj, ok := v.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("variant %q is not encoded as object", name)
+		}
+
+		res := maps.Clone(obj)
+		res[field] = name
+		return res, nil
+	}
+}
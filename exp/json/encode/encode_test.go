@@ -0,0 +1,69 @@
+package encode
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/rprtr258/assert"
+	"github.com/rprtr258/fun"
+)
+
+type User struct {
+	ID    int
+	Name  string
+	Email fun.Option[string]
+	Tags  []string
+}
+
+var encoderUser = Object(
+	Required("id", func(u User) int { return u.ID }, Int),
+	Required("name", func(u User) string { return u.Name }, String),
+	Option("email", func(u User) fun.Option[string] { return u.Email }, String),
+	Required("tags", func(u User) []string { return u.Tags }, List(String)),
+)
+
+func TestObject(t *testing.T) {
+	t.Parallel()
+
+	for name, test := range map[string]struct {
+		user     User
+		expected string
+	}{
+		"full": {
+			User{123, "Sam", fun.Valid("sam@example.com"), []string{"admin"}},
+			`{"email":"sam@example.com","id":123,"name":"Sam","tags":["admin"]}`,
+		},
+		"omitted": {
+			User{1, "Bob", fun.Invalid[string](), nil},
+			`{"id":1,"name":"Bob","tags":null}`,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := encoderUser.ToString(test.user)
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, got)
+		})
+	}
+}
+
+func TestNullable(t *testing.T) {
+	t.Parallel()
+
+	got, err := List(Nullable(Int)).ToString([]fun.Option[int]{fun.Valid(1), fun.Invalid[int]()})
+	assert.NoError(t, err)
+	assert.Equal(t, `[1,null]`, got)
+}
+
+func TestError(t *testing.T) {
+	t.Parallel()
+
+	_, err := Dict(List(Float)).ToString(map[string][]float64{"a": {1, math.NaN()}})
+	assert.EqualError(t, "json: unsupported value: NaN", err)
+
+	failing := Encoder[int](func(int) (any, error) { return nil, errors.New("boom") })
+	_, err = Dict(List(failing)).ToString(map[string][]int{"a": {1}})
+	assert.EqualError(t, "/a/0: boom", err)
+}
@@ -0,0 +1,37 @@
+package encode
+
+import (
+	"strconv"
+	"strings"
+)
+
+// EncodeError describes why and where encoding failed.
+type EncodeError struct {
+	// Path is a JSON Pointer to the value failed to encode, empty for the whole document.
+	Path string
+	Err  error
+}
+
+func (e *EncodeError) Error() string {
+	if e.Path == "" {
+		return e.Err.Error()
+	}
+	return e.Path + ": " + e.Err.Error()
+}
+
+func (e *EncodeError) Unwrap() error {
+	return e.Err
+}
+
+// withPath prepends path segment to path of error.
+func withPath(err error, segment string) error {
+	segment = "/" + strings.NewReplacer("~", "~0", "/", "~1").Replace(segment)
+	if e, ok := err.(*EncodeError); ok {
+		return &EncodeError{Path: segment + e.Path, Err: e.Err}
+	}
+	return &EncodeError{Path: segment, Err: err}
+}
+
+func indexSegment(i int) string {
+	return strconv.Itoa(i)
+}
@@ -0,0 +1,144 @@
+package json
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// DecodeError describes why and where decoding failed.
+type DecodeError struct {
+	// Path is a JSON Pointer to the value failed to decode, empty for the whole document.
+	Path string
+	// Expected and Actual are JSON kinds, set if error is a kind mismatch.
+	Expected, Actual string
+	// Err is the cause of error, if it is not a kind mismatch.
+	Err error
+}
+
+func (e *DecodeError) Error() string {
+	msg := ""
+	if e.Err != nil {
+		msg = e.Err.Error()
+	} else {
+		msg = fmt.Sprintf("expected %s, got %s", e.Expected, e.Actual)
+	}
+
+	if e.Path == "" {
+		return msg
+	}
+	return e.Path + ": " + msg
+}
+
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
+
+// DecodeErrors is a list of all errors found in document.
+type DecodeErrors []*DecodeError
+
+func (errs DecodeErrors) Error() string {
+	msgs := make([]string, len(errs))
+	for i, err := range errs {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+func (errs DecodeErrors) Unwrap() []error {
+	res := make([]error, len(errs))
+	for i, err := range errs {
+		res[i] = err
+	}
+	return res
+}
+
+func kindError(expected string, v value) error {
+	return &DecodeError{Expected: expected, Actual: v.kind()}
+}
+
+// decodeErrors converts any error to list of decode errors.
+func decodeErrors(err error) DecodeErrors {
+	switch err := err.(type) {
+	case DecodeErrors:
+		return err
+	case *DecodeError:
+		return DecodeErrors{err}
+	default:
+		return DecodeErrors{{Err: err}}
+	}
+}
+
+// collect appends errors of err, if any. It reports whether decoding of v should go on,
+// which is so while there are no errors, or if all of them are collected.
+func (errs *DecodeErrors) collect(v value, err error) bool {
+	if err != nil {
+		*errs = append(*errs, decodeErrors(err)...)
+	}
+	return len(*errs) == 0 || v.all
+}
+
+// add collects errors of err, if any, with path prepended by segment.
+func (errs *DecodeErrors) add(v value, err error, segment string) bool {
+	if err != nil {
+		err = withPath(err, segment)
+	}
+	return errs.collect(v, err)
+}
+
+// unique removes repeated errors, e.g. same kind mismatch reported by several
+// fields decoders.
+func (errs DecodeErrors) unique() DecodeErrors {
+	seen := make(map[string]struct{}, len(errs))
+	res := make(DecodeErrors, 0, len(errs))
+	for _, e := range errs {
+		msg := e.Error()
+		if _, ok := seen[msg]; ok {
+			continue
+		}
+		seen[msg] = struct{}{}
+		res = append(res, e)
+	}
+	return res
+}
+
+// pointerEscaper escapes path segment for JSON Pointer.
+var pointerEscaper = strings.NewReplacer("~", "~0", "/", "~1")
+
+// withPath prepends path segment to paths of errors. Errors are copied, since
+// the same error might be returned by decoder several times.
+func withPath(err error, segment string) error {
+	errs := decodeErrors(err)
+	segment = "/" + pointerEscaper.Replace(segment)
+	res := make(DecodeErrors, len(errs))
+	for i, e := range errs {
+		e := *e
+		e.Path = segment + e.Path
+		res[i] = &e
+	}
+	return res.err()
+}
+
+func indexSegment(i int) string {
+	return strconv.Itoa(i)
+}
+
+// err returns nil for empty list, single error or list itself.
+func (errs DecodeErrors) err() error {
+	switch len(errs) {
+	case 0:
+		return nil
+	case 1:
+		return errs[0]
+	default:
+		return errs
+	}
+}
+
+// firstError returns first of accumulated errors.
+func firstError(err error) error {
+	if errs, ok := err.(DecodeErrors); ok && len(errs) > 0 {
+		return errs[0]
+	}
+	return err
+}
@@ -5,192 +5,330 @@ package json
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"slices"
+	"strings"
 	"time"
 
 	"github.com/rprtr258/fun"
 )
 
-type Decoder[T any] func(any, *T) error
+// Decoder decodes value of JSON document to T. Document is parsed into compact list of
+// values referring to its text, without building tree of any, so numbers are taken
+// as they are written and no precision is lost.
+// Besides decoding, decoder describes values it accepts, see Schema.
+type Decoder[T any] struct {
+	decode func(value, *T) error
+	// schema describes accepted values, putting definitions of Lazy decoders to defs
+	schema func(defs *schemaDefs) map[string]any
+	// field is set if decoder decodes single object field, see Assign
+	field *fieldDecoder[T]
+}
 
-func (decoder Decoder[T]) ParseBytes(b []byte) (T, error) {
-	var v any
-	if err := json.Unmarshal(b, &v); err != nil {
+// Custom makes decoder from function decoding value as encoding/json decodes it into any,
+// with numbers as json.Number. Values it accepts cannot be described, so schema of it
+// accepts anything.
+func Custom[T any](decode func(any, *T) error) Decoder[T] {
+	return Decoder[T]{
+		decode: func(v value, res *T) error {
+			return decode(v.any(), res)
+		},
+		schema: anySchema,
+	}
+}
+
+// parseBytes decodes document, stopping at the first decode error unless all are asked for.
+func (decoder Decoder[T]) parseBytes(b []byte, all bool) (T, error) {
+	doc, err := parse(b)
+	if err != nil {
 		return *new(T), err
 	}
+
 	var t T
-	if err := decoder(v, &t); err != nil {
+	if err := decoder.decode(doc.root(all), &t); err != nil {
 		return t, err
 	}
 	return t, nil
 }
 
+// ParseBytes decodes JSON document. Only the first of decode errors is returned.
+func (decoder Decoder[T]) ParseBytes(b []byte) (T, error) {
+	t, err := decoder.parseBytes(b, false)
+	return t, firstError(err)
+}
+
 func (decoder Decoder[T]) ParseString(s string) (T, error) {
 	return decoder.ParseBytes([]byte(s))
 }
 
-func primitiveDecoder[T any](v any, res *T) error {
-	x, ok := v.(T)
-	if !ok {
-		return fmt.Errorf("not a %T", x)
+// ParseBytesAll decodes JSON document, reporting every error found in it as DecodeErrors,
+// which is useful for validation reports.
+func (decoder Decoder[T]) ParseBytesAll(b []byte) (T, error) {
+	t, err := decoder.parseBytes(b, true)
+	if _, ok := err.(*SyntaxError); ok || err == nil {
+		return t, err
 	}
-	*res = x
-	return nil
+	return t, decodeErrors(err).unique()
 }
 
-var Int Decoder[int] = func(v any, i *int) error {
-	var f float64
-	if err := primitiveDecoder(v, &f); err != nil {
-		return err
-	}
-	if f != float64(int(f)) {
-		return fmt.Errorf("not an int")
-	}
-	*i = int(f)
-	return nil
+func (decoder Decoder[T]) ParseStringAll(s string) (T, error) {
+	return decoder.ParseBytesAll([]byte(s))
 }
 
 var (
-	String Decoder[string]    = primitiveDecoder[string]
-	Bool   Decoder[bool]      = primitiveDecoder[bool]
-	Float  Decoder[float64]   = primitiveDecoder[float64]
-	Time   Decoder[time.Time] = func(a any, t *time.Time) error {
-		x, ok := a.(string)
-		if !ok {
-			return fmt.Errorf("not a string")
-		}
-		var err error
-		*t, err = time.Parse(time.RFC3339, x)
-		return err
+	String = Decoder[string]{
+		decode: func(v value, s *string) error {
+			if !v.is(kindString) {
+				return kindError("string", v)
+			}
+			*s = v.str()
+			return nil
+		},
+		schema: func(*schemaDefs) map[string]any {
+			return typeSchema("string")
+		},
+	}
+	Bool = Decoder[bool]{
+		decode: func(v value, b *bool) error {
+			switch v.node().kind {
+			case kindFalse:
+				*b = false
+			case kindTrue:
+				*b = true
+			default:
+				return kindError("boolean", v)
+			}
+			return nil
+		},
+		schema: func(*schemaDefs) map[string]any {
+			return typeSchema("boolean")
+		},
+	}
+	Time = Decoder[time.Time]{
+		decode: func(v value, t *time.Time) error {
+			if !v.is(kindString) {
+				return kindError("string", v)
+			}
+			var err error
+			if *t, err = time.Parse(time.RFC3339, v.str()); err != nil {
+				return &DecodeError{Err: err}
+			}
+			return nil
+		},
+		schema: func(*schemaDefs) map[string]any {
+			return map[string]any{"type": "string", "format": "date-time"}
+		},
 	}
 )
 
-func Any(v any, dest *any) error {
-	*dest = v
-	return nil
+// Any decodes value as is. Unlike with json.Unmarshal, numbers are json.Number, not float64.
+var Any = Decoder[any]{
+	decode: func(v value, dest *any) error {
+		*dest = v.any()
+		return nil
+	},
+	schema: anySchema,
 }
 
 func Nullable[T any](decoder Decoder[T]) Decoder[fun.Option[T]] {
-	return func(v any, res *fun.Option[T]) error {
-		if v == nil {
-			return nil
-		}
+	return Decoder[fun.Option[T]]{
+		decode: func(v value, res *fun.Option[T]) error {
+			if v.is(kindNull) {
+				*res = fun.Invalid[T]()
+				return nil
+			}
 
-		if err := decoder(v, &res.Value); err != nil {
-			return nil
-		}
+			var t T
+			if err := decoder.decode(v, &t); err != nil {
+				return err
+			}
 
-		res.Valid = true
-		return nil
+			*res = fun.Valid(t)
+			return nil
+		},
+		schema: func(defs *schemaDefs) map[string]any {
+			return map[string]any{"anyOf": []any{decoder.schema(defs), typeSchema("null")}}
+		},
 	}
 }
 
+// Dict decodes object with values of the same type, errors are reported in order of document.
 func Dict[T any](decoder Decoder[T]) Decoder[map[string]T] {
-	return func(v any, res *map[string]T) error {
-		vmap, ok := v.(map[string]any)
-		if !ok {
-			return fmt.Errorf("not a dict")
-		}
-
-		*res = make(map[string]T, len(vmap))
-		for k, v := range vmap {
-			var t T
-			if err := decoder(v, &t); err != nil {
-				return err
+	return Decoder[map[string]T]{
+		decode: func(v value, res *map[string]T) error {
+			if !v.is(kindObject) {
+				return kindError("object", v)
 			}
 
-			(*res)[k] = t
-		}
-		return nil
+			*res = map[string]T{}
+			var errs DecodeErrors
+			for key, val := range v.members() {
+				k := key.str()
+				var t T
+				err := decoder.decode(val, &t)
+				if err == nil {
+					(*res)[k] = t
+				} else if !errs.add(v, err, k) {
+					break
+				}
+			}
+			return errs.err()
+		},
+		schema: func(defs *schemaDefs) map[string]any {
+			return map[string]any{"type": "object", "additionalProperties": decoder.schema(defs)}
+		},
 	}
 }
 
 func List[T any](decoder Decoder[T]) Decoder[[]T] {
-	return func(v any, res *[]T) error {
-		switch v := v.(type) {
-		case nil: // parse null to nil slice
-			*res = nil
-		case []any:
-			*res = make([]T, len(v))
-			for i, v := range v {
-				if err := decoder(v, &(*res)[i]); err != nil {
-					return err
+	return Decoder[[]T]{
+		decode: func(v value, res *[]T) error {
+			switch v.node().kind {
+			case kindNull: // parse null to nil slice
+				*res = nil
+			case kindArray:
+				*res = make([]T, v.len())
+				var errs DecodeErrors
+				for i, elem := range v.elems() {
+					if !errs.add(v, decoder.decode(elem, &(*res)[i]), indexSegment(i)) {
+						break
+					}
 				}
+				return errs.err()
+			default:
+				return kindError("array", v)
 			}
-		default:
-			return fmt.Errorf("not a list")
-		}
-		return nil
+			return nil
+		},
+		schema: func(defs *schemaDefs) map[string]any {
+			return map[string]any{"type": []string{"array", "null"}, "items": decoder.schema(defs)}
+		},
 	}
 }
 
 func OneOf[T any](decoders ...Decoder[T]) Decoder[T] {
-	return func(v any, res *T) error {
-		errors := make([]error, len(decoders))
-		for i, decoder := range decoders {
-			var t T
-			if err := decoder(v, &t); err == nil {
-				*res = t
-				return nil
-			} else {
-				errors[i] = err
+	return Decoder[T]{
+		decode: func(v value, res *T) error {
+			msgs := make([]string, len(decoders))
+			for i, decoder := range decoders {
+				var t T
+				err := decoder.decode(v, &t)
+				if err == nil {
+					*res = t
+					return nil
+				}
+
+				msgs[i] = fmt.Sprintf("variant %d: %v", i, firstError(err))
 			}
-		}
-		return fmt.Errorf("all variants failed: %v", errors)
+			return &DecodeError{Err: fmt.Errorf("no variant matched: %s", strings.Join(msgs, "; "))}
+		},
+		schema: func(defs *schemaDefs) map[string]any {
+			return map[string]any{"anyOf": schemasOf(defs, decoders)}
+		},
 	}
 }
 
+// AndThen decodes value with decoder chosen by result of da. It is described in schema
+// by da only, since the next decoder depends on decoded value.
 func AndThen[A, B any](da Decoder[A], f func(A) Decoder[B]) Decoder[B] {
-	return func(v any, res *B) error {
-		var a A
-		if err := da(v, &a); err != nil {
-			return err
-		}
-		return f(a)(v, res)
+	return Decoder[B]{
+		decode: func(v value, res *B) error {
+			var a A
+			if err := da.decode(v, &a); err != nil {
+				return err
+			}
+			return f(a).decode(v, res)
+		},
+		schema: da.schema,
 	}
 }
 
+// Success decodes any value to x.
 func Success[T any](x T) Decoder[T] {
-	return func(_ any, res *T) error {
-		*res = x
-		return nil
+	return Decoder[T]{
+		decode: func(_ value, res *T) error {
+			*res = x
+			return nil
+		},
+		schema: anySchema,
 	}
 }
 
-func Null[T any](value T) Decoder[T] {
-	return func(v any, res *T) error {
-		if v != nil {
-			return fmt.Errorf("not null")
-		}
-		*res = value
-		return nil
+func Null[T any](x T) Decoder[T] {
+	return Decoder[T]{
+		decode: func(v value, res *T) error {
+			if !v.is(kindNull) {
+				return kindError("null", v)
+			}
+			*res = x
+			return nil
+		},
+		schema: func(*schemaDefs) map[string]any {
+			return typeSchema("null")
+		},
 	}
 }
 
 func Fail[T any](msg string) Decoder[T] {
-	return func(any, *T) error {
-		return fmt.Errorf("%s", msg)
+	return Decoder[T]{
+		decode: func(value, *T) error {
+			return &DecodeError{Err: errors.New(msg)}
+		},
+		schema: func(*schemaDefs) map[string]any {
+			return map[string]any{"not": map[string]any{}}
+		},
+	}
+}
+
+// fieldDecoder decodes value of single object field, telling what to do if it is missing.
+type fieldDecoder[T any] struct {
+	name   string
+	decode func(value, *T) error
+	// missing sets result if field is missing, field is required if it is nil
+	missing func(*T)
+	schema  func(*schemaDefs) map[string]any
+}
+
+// objectField makes decoder of object with single field. The field is kept in decoder,
+// so that it can be used by ObjectBuilder, see Assign.
+func objectField[T any](f fieldDecoder[T]) Decoder[T] {
+	return Decoder[T]{
+		decode: func(v value, res *T) error {
+			if !v.is(kindObject) {
+				return kindError("object", v)
+			}
+			v, ok := v.field(f.name)
+			switch {
+			case ok:
+				if err := f.decode(v, res); err != nil {
+					return withPath(err, f.name)
+				}
+			case f.missing == nil:
+				return withPath(&DecodeError{Err: errors.New("required field is missing")}, f.name)
+			default:
+				f.missing(res)
+			}
+			return nil
+		},
+		schema: func(defs *schemaDefs) map[string]any {
+			var required []string
+			if f.missing == nil {
+				required = []string{f.name}
+			}
+			return objectSchema(map[string]any{f.name: f.schema(defs)}, required)
+		},
+		field: &f,
 	}
 }
 
 // Decode a Required field.
 func (decoder Decoder[T]) Field(name string) Decoder[T] {
-	return func(v any, res *T) error {
-		vm, ok := v.(map[string]any)
-		if !ok {
-			return fmt.Errorf("not a dict")
-		}
-		v, ok = vm[name]
-		if !ok {
-			return fmt.Errorf("key %q not found", name)
-		}
-
-		if err := decoder(v, res); err != nil {
-			return err
-		}
-		return nil
-	}
+	return objectField(fieldDecoder[T]{
+		name:   name,
+		decode: decoder.decode,
+		schema: decoder.schema,
+	})
 }
 
 func (decoder Decoder[T]) At(names []string) Decoder[T] {
@@ -202,77 +340,107 @@ func (decoder Decoder[T]) At(names []string) Decoder[T] {
 }
 
 func (decoder Decoder[T]) Index(i int) Decoder[T] {
-	return func(v any, res *T) error {
-		vl, ok := v.([]any)
-		if !ok {
-			return fmt.Errorf("not a list")
-		}
+	return Decoder[T]{
+		decode: func(v value, res *T) error {
+			if !v.is(kindArray) {
+				return kindError("array", v)
+			}
 
-		if i < 0 || len(vl) <= i {
-			return fmt.Errorf("no such index %d", i)
-		}
+			for j, elem := range v.elems() {
+				if j != i {
+					continue
+				}
 
-		return decoder(vl[i], res)
+				if err := decoder.decode(elem, res); err != nil {
+					return withPath(err, indexSegment(i))
+				}
+				return nil
+			}
+			return withPath(&DecodeError{Err: fmt.Errorf("index out of range [0, %d)", v.len())}, indexSegment(i))
+		},
+		schema: func(defs *schemaDefs) map[string]any {
+			return indexSchema(defs, i, decoder)
+		},
 	}
 }
 
 func (da Decoder[T]) Optional(name string, fallback T) Decoder[T] {
-	return func(v any, res *T) error {
-		x, ok := v.(map[string]any)
-		if !ok {
-			return fmt.Errorf("not a dict")
-		}
-		v, ok = x[name]
-		if !ok {
+	return objectField(fieldDecoder[T]{
+		name:   name,
+		decode: da.decode,
+		missing: func(res *T) {
 			*res = fallback
-			return nil
-		}
-
-		if err := da(v, res); err != nil {
-			return err
-		}
-		return nil
-	}
+		},
+		schema: da.schema,
+	})
 }
 
+// Option decodes optional field, which is invalid if it is either missing or null,
+// as with unmarshaling fun.Option.
 func Option[T any](
 	name string,
 	da Decoder[T],
 ) Decoder[fun.Option[T]] {
-	return func(v any, res *fun.Option[T]) error {
-		x, ok := v.(map[string]any)
-		if !ok {
-			return nil
-		}
+	nullable := Nullable(da)
+	return objectField(fieldDecoder[fun.Option[T]]{
+		name:   name,
+		decode: nullable.decode,
+		missing: func(res *fun.Option[T]) {
+			*res = fun.Invalid[T]()
+		},
+		schema: nullable.schema,
+	})
+}
 
-		v, ok = x[name]
-		if !ok {
+// Lenient makes decoder ignore errors, leaving zero value instead.
+// Useful to keep behaviour of best-effort decoding of malformed data.
+// It is described in schema as accepting anything.
+func Lenient[T any](decoder Decoder[T]) Decoder[T] {
+	return Decoder[T]{
+		decode: func(v value, res *T) error {
+			if err := decoder.decode(v, res); err != nil {
+				*res = *new(T)
+			}
 			return nil
-		}
-
-		if err := da(v, &res.Value); err != nil {
-			return err
-		}
-		return nil
+		},
+		schema: anySchema,
 	}
 }
 
+// Validate checks decoded value. The check is not described in schema.
 func (d Decoder[T]) Validate(check func(T) error) Decoder[T] {
-	return func(v any, res *T) error {
-		if err := d(v, res); err != nil {
-			return err
-		}
-		return check(*res)
+	return Decoder[T]{
+		decode: func(v value, res *T) error {
+			if err := d.decode(v, res); err != nil {
+				return err
+			}
+			if err := check(*res); err != nil {
+				return &DecodeError{Err: err}
+			}
+			return nil
+		},
+		schema: d.schema,
 	}
 }
 
+// Std decodes value using encoding/json. Values it accepts are not described in schema.
 func Std[T any]() Decoder[T] {
-	return func(v any, res *T) error {
-		b, err := json.Marshal(v)
-		if err != nil {
-			return err
-		}
+	return Decoder[T]{
+		decode: func(v value, res *T) error {
+			b := v.raw()
+			if v.hidden != nil {
+				// text of value has fields hidden from decoders, so it is written anew without them
+				var err error
+				if b, err = json.Marshal(v.any()); err != nil {
+					return &DecodeError{Err: err}
+				}
+			}
 
-		return json.Unmarshal(b, res)
+			if err := json.Unmarshal(b, res); err != nil {
+				return &DecodeError{Err: err}
+			}
+			return nil
+		},
+		schema: anySchema,
 	}
 }
@@ -1,10 +1,15 @@
 package json
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
+	"math"
+	"strings"
 	"testing"
 
 	"github.com/rprtr258/assert"
+	"github.com/rprtr258/fun"
 )
 
 func ExampleAndThen() {
@@ -82,3 +87,743 @@ func TestListNull(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, result, []any(nil))
 }
+
+func TestErrorPath(t *testing.T) {
+	t.Parallel()
+
+	decoder := List(decoderUser).Field("users")
+	_, err := decoder.ParseString(`{"users": [{"id": 1, "name": "a", "email": "b"}, {"id": "2", "name": "c"}]}`)
+	var de *DecodeError
+	assert.True(t, errors.As(err, &de))
+	assert.Equal(t, "/users/1/id", de.Path)
+	assert.Equal(t, "number", de.Expected)
+	assert.Equal(t, "string", de.Actual)
+	assert.Equal(t, "/users/1/id: expected number, got string", err.Error())
+
+	_, err = Int.Field("a/b~c").ParseString(`{}`)
+	assert.Equal(t, "/a~1b~0c: required field is missing", err.Error())
+
+	_, err = String.Index(2).ParseString(`["a"]`)
+	assert.Equal(t, "/2: index out of range [0, 1)", err.Error())
+
+	_, err = Dict(Int).ParseString(`{"a": 1, "b": 1.5}`)
+	assert.Equal(t, "/b: not an integer: 1.5", err.Error())
+}
+
+func TestErrorAll(t *testing.T) {
+	t.Parallel()
+
+	decoder := List(decoderUser).Field("users")
+	_, err := decoder.ParseStringAll(`{"users": [{"id": 1.5, "name": "a", "email": "b"}, {"id": "2", "name": "c"}]}`)
+	var errs DecodeErrors
+	assert.True(t, errors.As(err, &errs))
+	assert.Equal(t, []string{
+		"/users/0/id: not an integer: 1.5",
+		"/users/1/id: expected number, got string",
+		"/users/1/email: required field is missing",
+	}, fun.Map[string](func(e *DecodeError) string { return e.Error() }, errs...))
+
+	_, err = decoderUser.ParseStringAll(`[]`)
+	assert.Equal(t, "expected object, got array", err.Error())
+
+	_, err = decoderUser.ParseStringAll(`{`)
+	var syntaxErr *SyntaxError
+	assert.True(t, errors.As(err, &syntaxErr))
+
+	// the same error returned for several values gets path of each
+	shared := &DecodeError{Err: errors.New("bad")}
+	_, err = List(Custom(func(any, *int) error { return shared })).ParseStringAll(`[1, 2]`)
+	assert.EqualError(t, "/0: bad; /1: bad", err)
+	assert.Equal(t, "", shared.Path)
+}
+
+func TestErrorAllLarge(t *testing.T) {
+	t.Parallel()
+
+	const n = 20000
+	doc := "[" + strings.Repeat(`"x",`, n-1) + `"x"]`
+	_, err := List(Int).ParseStringAll(doc)
+	var errs DecodeErrors
+	assert.True(t, errors.As(err, &errs))
+	assert.Equal(t, n, len(errs))
+	assert.Equal(t, "/19999: expected number, got string", errs[n-1].Error())
+
+	_, err = List(Int).ParseString(doc)
+	assert.EqualError(t, "/0: expected number, got string", err)
+}
+
+func TestErrorOneOf(t *testing.T) {
+	t.Parallel()
+
+	_, err := OneOf(Null(""), String).Field("x").ParseString(`{"x": true}`)
+	assert.Equal(t, "/x: no variant matched: variant 0: expected null, got boolean; variant 1: expected string, got boolean", err.Error())
+}
+
+func TestFailures(t *testing.T) {
+	t.Parallel()
+
+	type pair struct{ A, B int }
+	decoderPair := Map2(func(a, b int) pair { return pair{a, b} }, Int.Field("a"), Int.Field("b"))
+	id := func(x int) int { return x }
+
+	for name, test := range map[string]struct {
+		parse func(string) error
+		input string
+	}{
+		"Int":           {parseErr(Int), `"1"`},
+		"Int float":     {parseErr(Int), `1.5`},
+		"String":        {parseErr(String), `1`},
+		"Bool":          {parseErr(Bool), `null`},
+		"Float":         {parseErr(Float), `"1"`},
+		"Time kind":     {parseErr(Time), `1`},
+		"Time format":   {parseErr(Time), `"yesterday"`},
+		"Nullable":      {parseErr(Nullable(Int)), `"1"`},
+		"Dict kind":     {parseErr(Dict(Int)), `[]`},
+		"Dict value":    {parseErr(Dict(Int)), `{"a": "1"}`},
+		"List kind":     {parseErr(List(Int)), `{}`},
+		"List elem":     {parseErr(List(Int)), `[1, "2"]`},
+		"OneOf":         {parseErr(OneOf(Int, Null(0))), `"1"`},
+		"AndThen":       {parseErr(AndThen(Int, func(int) Decoder[int] { return Int })), `"1"`},
+		"AndThen next":  {parseErr(AndThen(Int.Field("v"), func(int) Decoder[int] { return Fail[int]("no") })), `{"v": 1}`},
+		"Null":          {parseErr(Null(0)), `0`},
+		"Fail":          {parseErr(Fail[int]("no")), `0`},
+		"Field kind":    {parseErr(Int.Field("a")), `[]`},
+		"Field miss":    {parseErr(Int.Field("a")), `{}`},
+		"Field value":   {parseErr(Int.Field("a")), `{"a": "1"}`},
+		"At":            {parseErr(Int.At([]string{"a", "b"})), `{"a": {}}`},
+		"Index kind":    {parseErr(Int.Index(0)), `{}`},
+		"Index range":   {parseErr(Int.Index(1)), `[1]`},
+		"Index value":   {parseErr(Int.Index(0)), `["1"]`},
+		"Optional":      {parseErr(Int.Optional("a", 0)), `{"a": "1"}`},
+		"Optional kind": {parseErr(Int.Optional("a", 0)), `[]`},
+		"Option":        {parseErr(Option("a", Int)), `{"a": "1"}`},
+		"Option kind":   {parseErr(Option("a", Int)), `[]`},
+		"Validate": {parseErr(Int.Validate(func(int) error {
+			return errors.New("invalid")
+		})), `1`},
+		"Std":  {parseErr(Std[int]()), `"1"`},
+		"Map":  {parseErr(Map(id, Int)), `"1"`},
+		"Map2": {parseErr(decoderPair), `{"a": 1, "b": "2"}`},
+		"Map3": {parseErr(decoderUser), `{"id": 1, "name": "a"}`},
+		"Map4": {parseErr(Map4(func(a, b, c int, d string) int { return 0 }, Int, Int, Int, String.Field("x"))), `1`},
+		"Map5": {parseErr(Map5(func(a, b, c, d, e int) int { return 0 }, Int, Int, Int, Int, Fail[int]("no"))), `1`},
+		"Map8": {parseErr(Map8(func(a, b, c, d, e, f, g, h int) int { return 0 }, Int, Int, Int, Int, Int, Int, Int, Fail[int]("no"))), `1`},
+	} {
+		if test.parse(test.input) == nil {
+			t.Errorf("%s: expected error on %s", name, test.input)
+		}
+	}
+}
+
+func parseErr[T any](decoder Decoder[T]) func(string) error {
+	return func(s string) error {
+		_, err := decoder.ParseString(s)
+		return err
+	}
+}
+
+func TestOption(t *testing.T) {
+	t.Parallel()
+
+	result, err := Option("a", Int).ParseString(`{"a": 1}`)
+	assert.NoError(t, err)
+	assert.Equal(t, fun.Valid(1), result)
+
+	result, err = Option("a", Int).ParseString(`{}`)
+	assert.NoError(t, err)
+	assert.Equal(t, fun.Invalid[int](), result)
+
+	result, err = Option("a", Int).ParseString(`{"a": null}`)
+	assert.NoError(t, err)
+	assert.Equal(t, fun.Invalid[int](), result)
+
+	_, err = Option("a", Int).ParseString(`{"a": "1"}`)
+	assert.EqualError(t, "/a: expected number, got string", err)
+
+	type user struct{ Email fun.Option[string] }
+	decoder := Object[user]().Field(
+		Assign(Option("email", String), func(u *user, email fun.Option[string]) { u.Email = email }),
+	).Unknown(UnknownError).Decoder()
+	for doc, want := range map[string]fun.Option[string]{
+		`{"email": "a@b.c"}`: fun.Valid("a@b.c"),
+		`{"email": null}`:    fun.Invalid[string](),
+		`{}`:                 fun.Invalid[string](),
+	} {
+		u, err := decoder.ParseString(doc)
+		assert.NoError(t, err)
+		assert.Equal(t, want, u.Email)
+	}
+
+	result, err = Nullable(Int).ParseString(`null`)
+	assert.NoError(t, err)
+	assert.Equal(t, fun.Invalid[int](), result)
+}
+
+func TestLenient(t *testing.T) {
+	t.Parallel()
+
+	result, err := Lenient(Nullable(Int)).ParseString(`"1"`)
+	assert.NoError(t, err)
+	assert.Equal(t, fun.Invalid[int](), result)
+
+	result, err = Lenient(Option("a", Int)).ParseString(`[]`)
+	assert.NoError(t, err)
+	assert.Equal(t, fun.Invalid[int](), result)
+
+	n, err := Lenient(Int.Field("a")).ParseString(`{"a": "1"}`)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, n)
+}
+
+func TestNumbers(t *testing.T) {
+	t.Parallel()
+
+	i, err := Int.ParseString(`9007199254740993`)
+	assert.NoError(t, err)
+	assert.Equal(t, 9007199254740993, i)
+
+	i, err = Int.ParseString(`1e3`)
+	assert.NoError(t, err)
+	assert.Equal(t, 1000, i)
+
+	_, err = Int.ParseString(`1.5`)
+	assert.EqualError(t, "not an integer: 1.5", err)
+
+	_, err = Int.ParseString(`1e100`)
+	assert.EqualError(t, "integer out of range: 1e100", err)
+
+	_, err = Int.ParseString(`99999999999999999999`)
+	assert.EqualError(t, "integer out of range: 99999999999999999999", err)
+
+	f, err := Float.ParseString(`0.1`)
+	assert.NoError(t, err)
+	assert.Equal(t, 0.1, f)
+
+	a, err := Any.ParseString(`[12345678901234567890]`)
+	assert.NoError(t, err)
+	assert.Equal(t, any([]any{json.Number("12345678901234567890")}), a)
+}
+
+func TestSyntaxError(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		doc    string
+		msg    string
+		offset int64
+	}{
+		{``, "unexpected end of JSON input", 0},
+		{` `, "unexpected end of JSON input", 1},
+		{`{"a": 1`, "unexpected end of JSON input", 7},
+		{`"abc`, "unexpected end of JSON input", 4},
+		{`tru`, "unexpected end of JSON input", 3},
+		{`1.`, "unexpected end of JSON input", 2},
+		{`1 2`, "invalid character '2' after top-level value", 3},
+		{`[] é`, "invalid character 'Ã' after top-level value", 4},
+		{"1\n\n}", "invalid character '}' after top-level value", 4},
+		{`01`, "invalid character '1' after top-level value", 2},
+		{`[1,]`, "invalid character ']' looking for beginning of value", 4},
+		{`{"a" 1}`, "invalid character '1' after object key", 6},
+		{`{"a": 1 2}`, "invalid character '2' after object key:value pair", 9},
+		{`[1 2]`, "invalid character '2' after array element", 4},
+		{`{1}`, "invalid character '1' looking for beginning of object key string", 2},
+		{"\"\t\"", "invalid character '\\t' in string literal", 2},
+		{`"\x"`, "invalid character 'x' in string escape code", 3},
+		{`"\u12x4"`, `invalid character 'x' in \u hexadecimal character escape`, 6},
+		{`-a`, "invalid character 'a' in numeric literal", 2},
+		{`1.a`, "invalid character 'a' after decimal point in numeric literal", 3},
+		{`1ea`, "invalid character 'a' in exponent of numeric literal", 3},
+		{`tx`, "invalid character 'x' in literal true (expecting 'r')", 2},
+		{strings.Repeat("[", 10001), "exceeded max depth", 10001},
+	} {
+		_, err := Any.ParseString(test.doc)
+		var syntaxErr *SyntaxError
+		assert.True(t, errors.As(err, &syntaxErr))
+		assert.EqualError(t, test.msg, err)
+		assert.Equal(t, test.offset, syntaxErr.Offset)
+	}
+
+	_, err := Int.ParseString(" 1 \n\t")
+	assert.NoError(t, err)
+}
+
+func TestStrings(t *testing.T) {
+	t.Parallel()
+
+	// escapes, surrogate pairs and invalid UTF-8 are decoded as encoding/json does
+	for _, doc := range []string{
+		`"plain"`,
+		`"a\"b\\c\/d\b\f\n\r\t"`,
+		`"\u00e9\u4e2d\ud83d\ude00"`,
+		`"\ud83d"`,
+		`"\ud83dx\ude00"`,
+		`"\ud83d\u0041"`,
+		"\"\xff\xc3\"",
+		`"é中"`,
+	} {
+		var want string
+		assert.NoError(t, json.Unmarshal([]byte(doc), &want))
+
+		s, err := String.ParseString(doc)
+		assert.NoError(t, err)
+		assert.Equal(t, want, s)
+	}
+}
+
+func TestSizedNumbers(t *testing.T) {
+	t.Parallel()
+
+	i8, err := Int8.ParseString(`-128`)
+	assert.NoError(t, err)
+	assert.Equal(t, int8(-128), i8)
+
+	_, err = Int8.ParseString(`128`)
+	assert.EqualError(t, "integer out of range: 128", err)
+
+	_, err = Int16.Field("a").ParseString(`{"a": 4e4}`)
+	assert.EqualError(t, "/a: integer out of range: 4e4", err)
+
+	i64, err := Int64.ParseString(`9223372036854775807`)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(math.MaxInt64), i64)
+
+	u8, err := Uint8.ParseString(`255`)
+	assert.NoError(t, err)
+	assert.Equal(t, uint8(255), u8)
+
+	_, err = Uint8.ParseString(`256`)
+	assert.EqualError(t, "integer out of range: 256", err)
+
+	_, err = Uint.ParseString(`-1`)
+	assert.EqualError(t, "integer out of range: -1", err)
+
+	u64, err := Uint64.ParseString(`18446744073709551615`)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(math.MaxUint64), u64)
+
+	_, err = Uint32.ParseString(`"1"`)
+	assert.EqualError(t, "expected number, got string", err)
+
+	// exponent and fractional notation is converted exactly
+	for input, want := range map[string]int64{
+		`9007199254740993.0`:       9007199254740993,
+		`1e16`:                     1e16,
+		`90071992547409930e-1`:     9007199254740993,
+		`9223372036854775807.00`:   math.MaxInt64,
+		`-9.223372036854775808e18`: math.MinInt64,
+		`0e-1000000`:               0,
+		`-0.0`:                     0,
+	} {
+		i64, err := Int64.ParseString(input)
+		assert.NoError(t, err)
+		assert.Equal(t, want, i64)
+	}
+	u64, err = Uint64.ParseString(`1.8446744073709551615e19`)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(math.MaxUint64), u64)
+
+	for input, want := range map[string]string{
+		`9223372036854775808.0`: "integer out of range: 9223372036854775808.0",
+		`1e1000000`:             "integer out of range: 1e1000000",
+		`9007199254740993.5`:    "not an integer: 9007199254740993.5",
+		`1e-1000000`:            "not an integer: 1e-1000000",
+	} {
+		_, err := Int64.ParseString(input)
+		assert.EqualError(t, want, err)
+	}
+	_, err = Uint64.ParseString(`1.8446744073709551616e19`)
+	assert.EqualError(t, "integer out of range: 1.8446744073709551616e19", err)
+}
+
+func TestBigNumbers(t *testing.T) {
+	t.Parallel()
+
+	n, err := Number.ParseString(`123456789012345678901234567890.5`)
+	assert.NoError(t, err)
+	assert.Equal(t, json.Number("123456789012345678901234567890.5"), n)
+
+	i, err := BigInt.ParseString(`123456789012345678901234567890`)
+	assert.NoError(t, err)
+	assert.Equal(t, "123456789012345678901234567890", i.String())
+
+	i, err = BigInt.ParseString(`1.5e30`)
+	assert.NoError(t, err)
+	assert.Equal(t, "1500000000000000000000000000000", i.String())
+
+	_, err = BigInt.ParseString(`1.5`)
+	assert.EqualError(t, "not an integer: 1.5", err)
+
+	i, err = BigInt.ParseString(`-1200e-2`)
+	assert.NoError(t, err)
+	assert.Equal(t, "-12", i.String())
+
+	// huge exponents are rejected without expanding the number
+	for _, doc := range []string{`1e10000000`, `1e100000000000000000000`, `1e10000`} {
+		_, err = BigInt.ParseString(doc)
+		assert.EqualError(t, "integer out of range: "+doc, err)
+	}
+	_, err = BigInt.ParseString(`1e-100000000000000000000`)
+	assert.EqualError(t, "not an integer: 1e-100000000000000000000", err)
+
+	i, err = BigInt.ParseString(`0e100000000000000000000`)
+	assert.NoError(t, err)
+	assert.Equal(t, "0", i.String())
+
+	i, err = BigIntDigits(3).ParseString(`9.99e2`)
+	assert.NoError(t, err)
+	assert.Equal(t, "999", i.String())
+
+	_, err = BigIntDigits(3).ParseString(`1000`)
+	assert.EqualError(t, "integer out of range: 1000", err)
+
+	f, err := BigFloat.ParseString(`123456789012345678901234567890.5`)
+	assert.NoError(t, err)
+	assert.Equal(t, "123456789012345678901234567890.5", f.Text('f', 1))
+}
+
+func TestNumberStrings(t *testing.T) {
+	t.Parallel()
+
+	i, err := IntString.ParseString(`"9007199254740993"`)
+	assert.NoError(t, err)
+	assert.Equal(t, 9007199254740993, i)
+
+	f, err := FloatString.ParseString(`"-1.5e-3"`)
+	assert.NoError(t, err)
+	assert.Equal(t, -1.5e-3, f)
+
+	for _, s := range []string{`""`, `" 1"`, `"1 "`, `"0x10"`, `"Inf"`, `"01"`, `"+1"`} {
+		_, err := FloatString.ParseString(s)
+		assert.EqualError(t, "invalid number: "+s, err)
+	}
+
+	_, err = IntString.ParseString(`1`)
+	assert.EqualError(t, "expected string, got number", err)
+}
+
+type Account struct {
+	ID    int
+	Name  string
+	Tags  []string
+	Extra map[string]any
+}
+
+var objectAccount = Object[Account]().Field(
+	Required("id", Int, func(a *Account, id int) { a.ID = id }),
+	Required("name", String, func(a *Account, name string) { a.Name = name }),
+	Optional("tags", List(String), nil, func(a *Account, tags []string) { a.Tags = tags }),
+)
+
+func TestObject(t *testing.T) {
+	t.Parallel()
+
+	const doc = `{"id": 1, "name": "Sam", "role": "admin", "age": 30}`
+
+	account, err := objectAccount.Decoder().ParseString(doc)
+	assert.NoError(t, err)
+	assert.Equal(t, Account{ID: 1, Name: "Sam"}, account)
+
+	account, err = objectAccount.Decoder().ParseString(`{"id": 1, "name": "Sam", "tags": ["a"]}`)
+	assert.NoError(t, err)
+	assert.Equal(t, Account{ID: 1, Name: "Sam", Tags: []string{"a"}}, account)
+
+	_, err = objectAccount.Unknown(UnknownError).Decoder().ParseStringAll(doc)
+	assert.EqualError(t, "/role: unknown field; /age: unknown field", err)
+
+	account, err = objectAccount.
+		CollectUnknown(func(a *Account, extra map[string]any) { a.Extra = extra }).
+		Decoder().
+		ParseString(doc)
+	assert.NoError(t, err)
+	assert.Equal(t, Account{ID: 1, Name: "Sam", Extra: map[string]any{"role": "admin", "age": json.Number("30")}}, account)
+
+	_, err = objectAccount.Unknown(UnknownError).Decoder().ParseStringAll(`{"name": 1, "tags": [1], "x": null}`)
+	assert.EqualError(t, "/id: required field is missing; /name: expected string, got number; /tags/0: expected string, got number; /x: unknown field", err)
+
+	_, err = objectAccount.Decoder().ParseString(`[]`)
+	assert.EqualError(t, "expected object, got array", err)
+}
+
+func TestObjectManyFields(t *testing.T) {
+	t.Parallel()
+
+	var fields []ObjectField[[20]int]
+	for i := range 20 {
+		fields = append(fields, Required(fmt.Sprint(i), Int, func(a *[20]int, x int) { a[i] = x }))
+	}
+
+	var sb strings.Builder
+	sb.WriteString("{")
+	for i := range 20 {
+		if i > 0 {
+			sb.WriteString(",")
+		}
+		fmt.Fprintf(&sb, `"%d": %d`, i, i*i)
+	}
+	sb.WriteString("}")
+
+	got, err := Object[[20]int]().Field(fields...).Decoder().ParseString(sb.String())
+	assert.NoError(t, err)
+	for i, x := range got {
+		assert.Equal(t, i*i, x)
+	}
+}
+
+type Shape interface{ isShape() }
+
+type Circle struct{ Radius float64 }
+
+type Rect struct{ Width, Height float64 }
+
+func (Circle) isShape() {}
+func (Rect) isShape()   {}
+
+var (
+	decoderCircle = Map(func(r float64) Shape { return Circle{r} }, Float.Field("radius"))
+	decoderRect   = Map2(func(w, h float64) Shape { return Rect{w, h} }, Float.Field("width"), Float.Field("height"))
+)
+
+func TestTagged(t *testing.T) {
+	t.Parallel()
+
+	decoder := List(Tagged("type", map[string]Decoder[Shape]{
+		"circle": decoderCircle,
+		"rect":   decoderRect,
+	}))
+
+	shapes, err := decoder.ParseString(`[{"type": "circle", "radius": 1}, {"type": "rect", "width": 2, "height": 3}]`)
+	assert.NoError(t, err)
+	assert.Equal(t, []Shape{Circle{1}, Rect{2, 3}}, shapes)
+
+	_, err = decoder.ParseStringAll(`[{"type": "square"}, {"radius": 1}, {"type": "rect", "width": 2}]`)
+	assert.EqualError(t, `/0/type: unknown type "square", expected one of "circle", "rect"; /1/type: required field is missing; /2/height: required field is missing`, err)
+
+	// strict variant does not see tag field
+	strict := Tagged("type", map[string]Decoder[Shape]{
+		"circle": Object[Shape]().Field(
+			Required("radius", Float, func(s *Shape, r float64) { *s = Circle{r} }),
+		).Unknown(UnknownError).Decoder(),
+	})
+	shape, err := strict.ParseString(`{"type": "circle", "radius": 1}`)
+	assert.NoError(t, err)
+	assert.Equal(t, Shape(Circle{1}), shape)
+
+	_, err = strict.ParseString(`{"type": "circle", "radius": 1, "color": "red"}`)
+	assert.EqualError(t, "/color: unknown field", err)
+
+	b, err := json.Marshal(Schema(strict))
+	assert.NoError(t, err)
+	assert.Equal(t, `{"$schema":"https://json-schema.org/draft/2020-12/schema","oneOf":[`+
+		`{"additionalProperties":false,"properties":{"radius":{"type":"number"},"type":{"const":"circle"}},"required":["type","radius"],"type":"object"}]}`,
+		string(b))
+}
+
+func TestUntagged(t *testing.T) {
+	t.Parallel()
+
+	decoder := Untagged(decoderCircle, decoderRect, Map(func(s string) Shape { return Circle{} }, String))
+
+	shape, err := decoder.ParseString(`{"width": 2, "height": 3}`)
+	assert.NoError(t, err)
+	assert.Equal(t, Shape(Rect{2, 3}), shape)
+
+	// rect misses one field, while circle misses one too, so first one wins
+	_, err = decoder.ParseString(`{"width": 2}`)
+	assert.EqualError(t, "/radius: required field is missing", err)
+
+	_, err = decoder.ParseStringAll(`{"width": "2", "radius": "1", "height": "3"}`)
+	assert.EqualError(t, "/radius: expected number, got string", err)
+
+	_, err = decoder.ParseStringAll(`{"width": "2", "height": 3}`)
+	assert.EqualError(t, "/radius: required field is missing", err)
+
+	// all variants failed on value itself, first one is reported
+	_, err = decoder.ParseString(`1`)
+	assert.EqualError(t, "expected object, got number", err)
+
+	// string variant failed on value itself, so rect is closer despite having more errors
+	_, err = Untagged(Map(func(s string) Shape { return Circle{} }, String), decoderRect).
+		ParseStringAll(`{"width": "2", "height": "3"}`)
+	assert.EqualError(t, "/width: expected number, got string; /height: expected number, got string", err)
+}
+
+type Comment struct {
+	Text    string
+	Replies []Comment
+}
+
+// Expr is either number or list of expressions.
+type Expr struct {
+	Number int
+	List   []Expr
+}
+
+type Dir map[string]Dir
+
+var (
+	decoderComment Decoder[Comment]
+	decoderExpr    Decoder[Expr]
+	decoderDir     Decoder[Dir]
+)
+
+func init() {
+	decoderComment = Map2(
+		func(text string, replies []Comment) Comment { return Comment{text, replies} },
+		String.Field("text"),
+		List(Lazy(func() Decoder[Comment] { return decoderComment })).Optional("replies", nil),
+	)
+	decoderExpr = OneOf(
+		Map(func(n int) Expr { return Expr{Number: n} }, Int),
+		Map(func(l []Expr) Expr { return Expr{List: l} }, List(Lazy(func() Decoder[Expr] { return decoderExpr }))),
+	)
+	decoderDir = Map(func(m map[string]Dir) Dir { return m }, Dict(Lazy(func() Decoder[Dir] { return decoderDir })))
+}
+
+func TestLazy(t *testing.T) {
+	t.Parallel()
+
+	comment, err := decoderComment.ParseString(`{"text": "a", "replies": [{"text": "b", "replies": [{"text": "c"}]}, {"text": "d"}]}`)
+	assert.NoError(t, err)
+	assert.Equal(t, Comment{"a", []Comment{{"b", []Comment{{"c", nil}}}, {"d", nil}}}, comment)
+
+	_, err = decoderComment.ParseString(`{"text": "a", "replies": [{"text": "b", "replies": [{"text": 1}]}]}`)
+	assert.EqualError(t, "/replies/0/replies/0/text: expected string, got number", err)
+
+	expr, err := decoderExpr.ParseString(`[1, [2, []]]`)
+	assert.NoError(t, err)
+	assert.Equal(t, Expr{List: []Expr{{Number: 1}, {List: []Expr{{Number: 2}, {List: []Expr{}}}}}}, expr)
+
+	dir, err := decoderDir.ParseString(`{"usr": {"bin": {}, "lib": {}}, "tmp": {}}`)
+	assert.NoError(t, err)
+	assert.Equal(t, Dir{"usr": {"bin": {}, "lib": {}}, "tmp": {}}, dir)
+
+	calls := 0
+	decoder := Lazy(func() Decoder[int] {
+		calls++
+		return Int
+	})
+	_, _ = List(decoder).ParseString(`[1, 2, 3]`)
+	assert.Equal(t, 1, calls)
+}
+
+func TestDeepNesting(t *testing.T) {
+	t.Parallel()
+
+	const depth = 4000
+	doc := strings.Repeat(`{"text": "x", "replies": [`, depth) + `{"text": "leaf"}` + strings.Repeat(`]}`, depth)
+
+	comment, err := decoderComment.ParseString(doc)
+	assert.NoError(t, err)
+	for range depth {
+		comment = comment.Replies[0]
+	}
+	assert.Equal(t, "leaf", comment.Text)
+
+	// every comment adds object and array, plus leaf object
+	_, err = decoderComment.MaxDepth(2*depth + 1).ParseString(doc)
+	assert.NoError(t, err)
+
+	_, err = decoderComment.MaxDepth(2 * depth).ParseString(doc)
+	assert.EqualError(t, "nesting exceeds depth 8000", err)
+
+	_, err = Int.MaxDepth(0).ParseString(`1`)
+	assert.NoError(t, err)
+
+	_, err = Any.MaxDepth(0).ParseString(`[]`)
+	assert.EqualError(t, "nesting exceeds depth 0", err)
+}
+
+func TestSchema(t *testing.T) {
+	t.Parallel()
+
+	const dialect = `"$schema":"https://json-schema.org/draft/2020-12/schema"`
+	for name, test := range map[string]struct {
+		schema   map[string]any
+		expected string
+	}{
+		"primitives": {
+			Schema(List(Nullable(Int8))),
+			`{` + dialect + `,"items":{"anyOf":[{"maximum":127,"minimum":-128,"type":"integer"},{"type":"null"}]},"type":["array","null"]}`,
+		},
+		"dict": {
+			Schema(Dict(Time)),
+			`{` + dialect + `,"additionalProperties":{"format":"date-time","type":"string"},"type":"object"}`,
+		},
+		"fields": {
+			Schema(decoderUser),
+			`{` + dialect + `,"properties":{"email":{"type":"string"},"id":{"type":"integer"},"name":{"type":"string"}},"required":["id","name","email"],"type":"object"}`,
+		},
+		"object": {
+			Schema(objectAccount.Unknown(UnknownError).Decoder()),
+			`{` + dialect + `,"additionalProperties":false,"properties":{"id":{"type":"integer"},"name":{"type":"string"},"tags":{"items":{"type":"string"},"type":["array","null"]}},"required":["id","name"],"type":"object"}`,
+		},
+		"one of": {
+			Schema(OneOf(Int, Null(0), Fail[int]("unsupported"))),
+			`{` + dialect + `,"anyOf":[{"type":"integer"},{"type":"null"},{"not":{}}]}`,
+		},
+		"and then": {
+			Schema(AndThen(Int.Field("version"), func(int) Decoder[int] { return Int.Field("x") })),
+			`{` + dialect + `,"properties":{"version":{"type":"integer"}},"required":["version"],"type":"object"}`,
+		},
+		"constraints": {
+			Schema(String.Constrain(MinLength(1), Pattern("^[a-z]+$")).Validate(func(string) error { return nil })),
+			`{` + dialect + `,"minLength":1,"pattern":"^[a-z]+$","type":"string"}`,
+		},
+		"tagged": {
+			Schema(Tagged("type", map[string]Decoder[Shape]{"circle": decoderCircle, "rect": decoderRect})),
+			`{` + dialect + `,"oneOf":[` +
+				`{"properties":{"radius":{"type":"number"},"type":{"const":"circle"}},"required":["type","radius"],"type":"object"},` +
+				`{"properties":{"height":{"type":"number"},"type":{"const":"rect"},"width":{"type":"number"}},"required":["type","width","height"],"type":"object"}]}`,
+		},
+		"recursive": {
+			Schema(decoderExpr),
+			`{"$defs":{"Expr":{"anyOf":[{"type":"integer"},{"items":{"$ref":"#/$defs/Expr"},"type":["array","null"]}]}},` +
+				dialect + `,"anyOf":[{"type":"integer"},{"items":{"$ref":"#/$defs/Expr"},"type":["array","null"]}]}`,
+		},
+		"custom": {
+			Schema(List(Custom(func(v any, s *string) error { *s = v.(string); return nil }))),
+			`{` + dialect + `,"items":{},"type":["array","null"]}`,
+		},
+		"lenient": {
+			Schema(Lenient(Int)),
+			`{` + dialect + `}`,
+		},
+		"anything": {
+			Schema(Map2(func(any, int) int { return 0 }, Any, Success(0))),
+			`{` + dialect + `}`,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			b, err := json.Marshal(test.schema)
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, string(b))
+		})
+	}
+
+	// recursive decoder making new Lazy on every use is described up to fixed depth
+	var decoderNested func() Decoder[Dir]
+	decoderNested = func() Decoder[Dir] {
+		return Map(func(m map[string]Dir) Dir { return m }, Dict(Lazy(decoderNested)))
+	}
+	defs := Schema(decoderNested())["$defs"].(map[string]any)
+	assert.Equal(t, maxLazyExpansions, len(defs))
+	assert.Equal(t, any(map[string]any{"additionalProperties": map[string]any{}, "type": "object"}), defs["Dir_31"])
+}
+
+func TestConstrain(t *testing.T) {
+	t.Parallel()
+
+	decoder := List(Int.Constrain(Minimum(0), Maximum(10), Enum(1, 2, 3, 20))).Constrain(MaxItems[int](2))
+
+	xs, err := decoder.ParseString(`[1, 2]`)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{1, 2}, xs)
+
+	_, err = decoder.ParseStringAll(`[-1, 20, 3]`)
+	assert.EqualError(t, "/0: must be at least 0, got -1; /0: must be one of [1 2 3 20], got -1; /1: must be at most 10, got 20", err)
+
+	_, err = decoder.ParseString(`[1, 2, 3]`)
+	assert.EqualError(t, "must have at most 2 items", err)
+}
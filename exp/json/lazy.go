@@ -0,0 +1,50 @@
+package json
+
+import (
+	"fmt"
+	"sync"
+)
+
+// lazyID identifies Lazy decoder in schema definitions. It is not zero-sized,
+// so that pointers to different ones are different.
+type lazyID struct{ _ byte }
+
+// Lazy makes decoder on first use, which allows to define decoders of recursive types:
+//
+//	var decoderTree Decoder[Tree]
+//
+//	func init() {
+//		decoderTree = Map2(newTree,
+//			Int.Field("value"),
+//			List(Lazy(func() Decoder[Tree] { return decoderTree })).Field("children"),
+//		)
+//	}
+//
+// Referring to the same decoder, as above, instead of making new one on every call of f
+// also lets Schema describe recursion by reference.
+func Lazy[T any](f func() Decoder[T]) Decoder[T] {
+	decoder := sync.OnceValue(f)
+	id := new(lazyID)
+	return Decoder[T]{
+		decode: func(v value, res *T) error {
+			return decoder().decode(v, res)
+		},
+		schema: func(defs *schemaDefs) map[string]any {
+			return lazySchema(defs, id, decoder)
+		},
+	}
+}
+
+// MaxDepth makes decoder fail on documents with arrays and objects nested deeper than depth,
+// so that hostile input cannot make recursive decoders exhaust stack. Depth of scalar is 0, of [] is 1.
+func (decoder Decoder[T]) MaxDepth(depth int) Decoder[T] {
+	return Decoder[T]{
+		decode: func(v value, res *T) error {
+			if !v.withinDepth(depth) {
+				return &DecodeError{Err: fmt.Errorf("nesting exceeds depth %d", depth)}
+			}
+			return decoder.decode(v, res)
+		},
+		schema: decoder.schema,
+	}
+}
@@ -4,14 +4,17 @@ func Map[T0, T any](
 	d0 func(T0) T,
 	decoder Decoder[T0],
 ) Decoder[T] {
-	return func(v any, res *T) error {
-		var dest0 T0
-		if err := decoder(v, &dest0); err != nil {
-			return nil
-		}
+	return Decoder[T]{
+		decode: func(v value, res *T) error {
+			var dest0 T0
+			if err := decoder.decode(v, &dest0); err != nil {
+				return err
+			}
 
-		*res = d0(dest0)
-		return nil
+			*res = d0(dest0)
+			return nil
+		},
+		schema: decoder.schema,
 	}
 }
 
@@ -20,19 +23,29 @@ func Map2[T0, T1, T any](
 	d0 Decoder[T0],
 	d1 Decoder[T1],
 ) Decoder[T] {
-	return func(v any, res *T) error {
-		var dest0 T0
-		if err := d0(v, &dest0); err != nil {
-			return err
-		}
-
-		var dest1 T1
-		if err := d1(v, &dest1); err != nil {
-			return err
-		}
-
-		*res = combine(dest0, dest1)
-		return nil
+	return Decoder[T]{
+		decode: func(v value, res *T) error {
+			var errs DecodeErrors
+
+			var dest0 T0
+			if !errs.collect(v, d0.decode(v, &dest0)) {
+				return errs.err()
+			}
+			var dest1 T1
+			if !errs.collect(v, d1.decode(v, &dest1)) {
+				return errs.err()
+			}
+
+			if len(errs) != 0 {
+				return errs.err()
+			}
+
+			*res = combine(dest0, dest1)
+			return nil
+		},
+		schema: func(defs *schemaDefs) map[string]any {
+			return allOf(d0.schema(defs), d1.schema(defs))
+		},
 	}
 }
 
@@ -42,22 +55,33 @@ func Map3[T0, T1, T2, T any](
 	d1 Decoder[T1],
 	d2 Decoder[T2],
 ) Decoder[T] {
-	return func(v any, res *T) error {
-		var dest0 T0
-		if err := d0(v, &dest0); err != nil {
-			return err
-		}
-		var dest1 T1
-		if err := d1(v, &dest1); err != nil {
-			return err
-		}
-		var dest2 T2
-		if err := d2(v, &dest2); err != nil {
-			return err
-		}
-
-		*res = combine(dest0, dest1, dest2)
-		return nil
+	return Decoder[T]{
+		decode: func(v value, res *T) error {
+			var errs DecodeErrors
+
+			var dest0 T0
+			if !errs.collect(v, d0.decode(v, &dest0)) {
+				return errs.err()
+			}
+			var dest1 T1
+			if !errs.collect(v, d1.decode(v, &dest1)) {
+				return errs.err()
+			}
+			var dest2 T2
+			if !errs.collect(v, d2.decode(v, &dest2)) {
+				return errs.err()
+			}
+
+			if len(errs) != 0 {
+				return errs.err()
+			}
+
+			*res = combine(dest0, dest1, dest2)
+			return nil
+		},
+		schema: func(defs *schemaDefs) map[string]any {
+			return allOf(d0.schema(defs), d1.schema(defs), d2.schema(defs))
+		},
 	}
 }
 
@@ -68,26 +92,37 @@ func Map4[T0, T1, T2, T3, T any](
 	d2 Decoder[T2],
 	d3 Decoder[T3],
 ) Decoder[T] {
-	return func(v any, res *T) error {
-		var dest0 T0
-		if err := d0(v, &dest0); err != nil {
-			return err
-		}
-		var dest1 T1
-		if err := d1(v, &dest1); err != nil {
-			return err
-		}
-		var dest2 T2
-		if err := d2(v, &dest2); err != nil {
-			return err
-		}
-		var dest3 T3
-		if err := d3(v, &dest3); err != nil {
-			return err
-		}
-
-		*res = combine(dest0, dest1, dest2, dest3)
-		return nil
+	return Decoder[T]{
+		decode: func(v value, res *T) error {
+			var errs DecodeErrors
+
+			var dest0 T0
+			if !errs.collect(v, d0.decode(v, &dest0)) {
+				return errs.err()
+			}
+			var dest1 T1
+			if !errs.collect(v, d1.decode(v, &dest1)) {
+				return errs.err()
+			}
+			var dest2 T2
+			if !errs.collect(v, d2.decode(v, &dest2)) {
+				return errs.err()
+			}
+			var dest3 T3
+			if !errs.collect(v, d3.decode(v, &dest3)) {
+				return errs.err()
+			}
+
+			if len(errs) != 0 {
+				return errs.err()
+			}
+
+			*res = combine(dest0, dest1, dest2, dest3)
+			return nil
+		},
+		schema: func(defs *schemaDefs) map[string]any {
+			return allOf(d0.schema(defs), d1.schema(defs), d2.schema(defs), d3.schema(defs))
+		},
 	}
 }
 
@@ -99,30 +134,148 @@ func Map5[T0, T1, T2, T3, T4, T any](
 	d3 Decoder[T3],
 	d4 Decoder[T4],
 ) Decoder[T] {
-	return func(v any, res *T) error {
-		var dest0 T0
-		if err := d0(v, &dest0); err != nil {
-			return err
-		}
-		var dest1 T1
-		if err := d1(v, &dest1); err != nil {
-			return err
-		}
-		var dest2 T2
-		if err := d2(v, &dest2); err != nil {
-			return err
-		}
-		var dest3 T3
-		if err := d3(v, &dest3); err != nil {
-			return err
-		}
-		var dest4 T4
-		if err := d4(v, &dest4); err != nil {
-			return err
-		}
-
-		*res = combine(dest0, dest1, dest2, dest3, dest4)
-		return nil
+	return Decoder[T]{
+		decode: func(v value, res *T) error {
+			var errs DecodeErrors
+
+			var dest0 T0
+			if !errs.collect(v, d0.decode(v, &dest0)) {
+				return errs.err()
+			}
+			var dest1 T1
+			if !errs.collect(v, d1.decode(v, &dest1)) {
+				return errs.err()
+			}
+			var dest2 T2
+			if !errs.collect(v, d2.decode(v, &dest2)) {
+				return errs.err()
+			}
+			var dest3 T3
+			if !errs.collect(v, d3.decode(v, &dest3)) {
+				return errs.err()
+			}
+			var dest4 T4
+			if !errs.collect(v, d4.decode(v, &dest4)) {
+				return errs.err()
+			}
+
+			if len(errs) != 0 {
+				return errs.err()
+			}
+
+			*res = combine(dest0, dest1, dest2, dest3, dest4)
+			return nil
+		},
+		schema: func(defs *schemaDefs) map[string]any {
+			return allOf(d0.schema(defs), d1.schema(defs), d2.schema(defs), d3.schema(defs), d4.schema(defs))
+		},
+	}
+}
+
+func Map6[T0, T1, T2, T3, T4, T5, T any](
+	combine func(T0, T1, T2, T3, T4, T5) T,
+	d0 Decoder[T0],
+	d1 Decoder[T1],
+	d2 Decoder[T2],
+	d3 Decoder[T3],
+	d4 Decoder[T4],
+	d5 Decoder[T5],
+) Decoder[T] {
+	return Decoder[T]{
+		decode: func(v value, res *T) error {
+			var errs DecodeErrors
+
+			var dest0 T0
+			if !errs.collect(v, d0.decode(v, &dest0)) {
+				return errs.err()
+			}
+			var dest1 T1
+			if !errs.collect(v, d1.decode(v, &dest1)) {
+				return errs.err()
+			}
+			var dest2 T2
+			if !errs.collect(v, d2.decode(v, &dest2)) {
+				return errs.err()
+			}
+			var dest3 T3
+			if !errs.collect(v, d3.decode(v, &dest3)) {
+				return errs.err()
+			}
+			var dest4 T4
+			if !errs.collect(v, d4.decode(v, &dest4)) {
+				return errs.err()
+			}
+			var dest5 T5
+			if !errs.collect(v, d5.decode(v, &dest5)) {
+				return errs.err()
+			}
+
+			if len(errs) != 0 {
+				return errs.err()
+			}
+
+			*res = combine(dest0, dest1, dest2, dest3, dest4, dest5)
+			return nil
+		},
+		schema: func(defs *schemaDefs) map[string]any {
+			return allOf(d0.schema(defs), d1.schema(defs), d2.schema(defs), d3.schema(defs), d4.schema(defs), d5.schema(defs))
+		},
+	}
+}
+
+func Map7[T0, T1, T2, T3, T4, T5, T6, T any](
+	combine func(T0, T1, T2, T3, T4, T5, T6) T,
+	d0 Decoder[T0],
+	d1 Decoder[T1],
+	d2 Decoder[T2],
+	d3 Decoder[T3],
+	d4 Decoder[T4],
+	d5 Decoder[T5],
+	d6 Decoder[T6],
+) Decoder[T] {
+	return Decoder[T]{
+		decode: func(v value, res *T) error {
+			var errs DecodeErrors
+
+			var dest0 T0
+			if !errs.collect(v, d0.decode(v, &dest0)) {
+				return errs.err()
+			}
+			var dest1 T1
+			if !errs.collect(v, d1.decode(v, &dest1)) {
+				return errs.err()
+			}
+			var dest2 T2
+			if !errs.collect(v, d2.decode(v, &dest2)) {
+				return errs.err()
+			}
+			var dest3 T3
+			if !errs.collect(v, d3.decode(v, &dest3)) {
+				return errs.err()
+			}
+			var dest4 T4
+			if !errs.collect(v, d4.decode(v, &dest4)) {
+				return errs.err()
+			}
+			var dest5 T5
+			if !errs.collect(v, d5.decode(v, &dest5)) {
+				return errs.err()
+			}
+			var dest6 T6
+			if !errs.collect(v, d6.decode(v, &dest6)) {
+				return errs.err()
+			}
+
+			if len(errs) != 0 {
+				return errs.err()
+			}
+
+			*res = combine(dest0, dest1, dest2, dest3, dest4, dest5, dest6)
+			return nil
+		},
+		schema: func(defs *schemaDefs) map[string]any {
+			return allOf(d0.schema(defs), d1.schema(defs), d2.schema(defs), d3.schema(defs), d4.schema(defs), d5.schema(defs), d6.schema(defs))
+		},
 	}
 }
 
@@ -137,41 +290,52 @@ func Map8[T0, T1, T2, T3, T4, T5, T6, T7, T any](
 	d6 Decoder[T6],
 	d7 Decoder[T7],
 ) Decoder[T] {
-	return func(v any, res *T) error {
-		var dest0 T0
-		if err := d0(v, &dest0); err != nil {
-			return err
-		}
-		var dest1 T1
-		if err := d1(v, &dest1); err != nil {
-			return err
-		}
-		var dest2 T2
-		if err := d2(v, &dest2); err != nil {
-			return err
-		}
-		var dest3 T3
-		if err := d3(v, &dest3); err != nil {
-			return err
-		}
-		var dest4 T4
-		if err := d4(v, &dest4); err != nil {
-			return err
-		}
-		var dest5 T5
-		if err := d5(v, &dest5); err != nil {
-			return err
-		}
-		var dest6 T6
-		if err := d6(v, &dest6); err != nil {
-			return err
-		}
-		var dest7 T7
-		if err := d7(v, &dest7); err != nil {
-			return err
-		}
-
-		*res = combine(dest0, dest1, dest2, dest3, dest4, dest5, dest6, dest7)
-		return nil
+	return Decoder[T]{
+		decode: func(v value, res *T) error {
+			var errs DecodeErrors
+
+			var dest0 T0
+			if !errs.collect(v, d0.decode(v, &dest0)) {
+				return errs.err()
+			}
+			var dest1 T1
+			if !errs.collect(v, d1.decode(v, &dest1)) {
+				return errs.err()
+			}
+			var dest2 T2
+			if !errs.collect(v, d2.decode(v, &dest2)) {
+				return errs.err()
+			}
+			var dest3 T3
+			if !errs.collect(v, d3.decode(v, &dest3)) {
+				return errs.err()
+			}
+			var dest4 T4
+			if !errs.collect(v, d4.decode(v, &dest4)) {
+				return errs.err()
+			}
+			var dest5 T5
+			if !errs.collect(v, d5.decode(v, &dest5)) {
+				return errs.err()
+			}
+			var dest6 T6
+			if !errs.collect(v, d6.decode(v, &dest6)) {
+				return errs.err()
+			}
+			var dest7 T7
+			if !errs.collect(v, d7.decode(v, &dest7)) {
+				return errs.err()
+			}
+
+			if len(errs) != 0 {
+				return errs.err()
+			}
+
+			*res = combine(dest0, dest1, dest2, dest3, dest4, dest5, dest6, dest7)
+			return nil
+		},
+		schema: func(defs *schemaDefs) map[string]any {
+			return allOf(d0.schema(defs), d1.schema(defs), d2.schema(defs), d3.schema(defs), d4.schema(defs), d5.schema(defs), d6.schema(defs), d7.schema(defs))
+		},
 	}
 }
@@ -0,0 +1,300 @@
+package json
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// bigInteger converts JSON number in any notation to integer without losing precision,
+// failing if it is fractional or has more than digits decimal digits. Number is checked
+// before being expanded, so that huge exponents like 1e1000000000 are rejected cheaply.
+func bigInteger(x json.Number, digits int) (*big.Int, error) {
+	s, neg := strings.CutPrefix(string(x), "-")
+	mantissa, exponent := s, ""
+	if i := strings.IndexAny(s, "eE"); i != -1 {
+		mantissa, exponent = s[:i], s[i+1:]
+	}
+	whole, frac, _ := strings.Cut(mantissa, ".")
+
+	var exp int64
+	if exponent != "" {
+		var err error
+		if exp, err = strconv.ParseInt(exponent, 10, 32); err != nil {
+			// too far from zero either way for any integer of sane size
+			exp = math.MaxInt32
+			if exponent[0] == '-' {
+				exp = math.MinInt32
+			}
+		}
+	}
+
+	// number is significant digits multiplied by 10^exp
+	significant := strings.TrimLeft(whole+frac, "0")
+	exp -= int64(len(frac))
+	if significant == "" {
+		return new(big.Int), nil
+	}
+	if exp < 0 {
+		trimmed := strings.TrimRight(significant, "0")
+		if int64(len(significant)-len(trimmed)) < -exp {
+			return nil, &DecodeError{Err: fmt.Errorf("not an integer: %s", x)}
+		}
+		significant, exp = significant[:int64(len(significant))+exp], 0
+	}
+	if int64(len(significant))+exp > int64(digits) {
+		return nil, &DecodeError{Err: fmt.Errorf("integer out of range: %s", x)}
+	}
+
+	n, _ := new(big.Int).SetString(significant, 10)
+	if exp > 0 {
+		n.Mul(n, new(big.Int).Exp(big.NewInt(10), big.NewInt(exp), nil))
+	}
+	if neg {
+		n.Neg(n)
+	}
+	return n, nil
+}
+
+// integer converts JSON number to integer, failing if it is fractional or does not fit in bits.
+func integer(v value, bits int) (int64, error) {
+	if !v.is(kindNumber) {
+		return 0, kindError("number", v)
+	}
+
+	x := json.Number(v.raw())
+	i, err := strconv.ParseInt(string(x), 10, bits)
+	if err == nil {
+		return i, nil
+	}
+	if errors.Is(err, strconv.ErrRange) {
+		return 0, &DecodeError{Err: fmt.Errorf("integer out of range: %s", x)}
+	}
+
+	// fractional or exponent notation, like 1.0 or 1e3, 64 bit integers have at most 20 digits
+	n, err := bigInteger(x, 20)
+	if err != nil {
+		return 0, err
+	}
+	if limit := new(big.Int).Lsh(big.NewInt(1), uint(bits-1)); n.Cmp(limit) >= 0 || n.Cmp(limit.Neg(limit)) < 0 {
+		return 0, &DecodeError{Err: fmt.Errorf("integer out of range: %s", x)}
+	}
+	return n.Int64(), nil
+}
+
+// unsigned converts JSON number to unsigned integer, failing if it is fractional, negative
+// or does not fit in bits.
+func unsigned(v value, bits int) (uint64, error) {
+	if !v.is(kindNumber) {
+		return 0, kindError("number", v)
+	}
+
+	x := json.Number(v.raw())
+	i, err := strconv.ParseUint(string(x), 10, bits)
+	if err == nil {
+		return i, nil
+	}
+	if errors.Is(err, strconv.ErrRange) {
+		return 0, &DecodeError{Err: fmt.Errorf("integer out of range: %s", x)}
+	}
+
+	// negative, fractional or exponent notation, like -1, 1.0 or 1e3
+	n, err := bigInteger(x, 20)
+	if err != nil {
+		return 0, err
+	}
+	if n.Sign() < 0 || n.BitLen() > bits {
+		return 0, &DecodeError{Err: fmt.Errorf("integer out of range: %s", x)}
+	}
+	return n.Uint64(), nil
+}
+
+func signedDecoder[T ~int | ~int8 | ~int16 | ~int32 | ~int64](bits int) Decoder[T] {
+	return Decoder[T]{
+		decode: func(v value, res *T) error {
+			x, err := integer(v, bits)
+			if err != nil {
+				return err
+			}
+			*res = T(x)
+			return nil
+		},
+		schema: func(*schemaDefs) map[string]any {
+			return integerSchema(true, bits)
+		},
+	}
+}
+
+func unsignedDecoder[T ~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64](bits int) Decoder[T] {
+	return Decoder[T]{
+		decode: func(v value, res *T) error {
+			x, err := unsigned(v, bits)
+			if err != nil {
+				return err
+			}
+			*res = T(x)
+			return nil
+		},
+		schema: func(*schemaDefs) map[string]any {
+			return integerSchema(false, bits)
+		},
+	}
+}
+
+// Integer decoders fail on fractional numbers and numbers not fitting into type.
+var (
+	Int    = signedDecoder[int](strconv.IntSize)
+	Int8   = signedDecoder[int8](8)
+	Int16  = signedDecoder[int16](16)
+	Int32  = signedDecoder[int32](32)
+	Int64  = signedDecoder[int64](64)
+	Uint   = unsignedDecoder[uint](strconv.IntSize)
+	Uint8  = unsignedDecoder[uint8](8)
+	Uint16 = unsignedDecoder[uint16](16)
+	Uint32 = unsignedDecoder[uint32](32)
+	Uint64 = unsignedDecoder[uint64](64)
+)
+
+var Float = Decoder[float64]{
+	decode: func(v value, f *float64) error {
+		if !v.is(kindNumber) {
+			return kindError("number", v)
+		}
+
+		x, err := strconv.ParseFloat(string(v.raw()), 64)
+		if err != nil {
+			return &DecodeError{Err: fmt.Errorf("number out of range: %s", v.raw())}
+		}
+		*f = x
+		return nil
+	},
+	schema: func(*schemaDefs) map[string]any {
+		return typeSchema("number")
+	},
+}
+
+// Number decodes number losslessly, as it is written in document.
+var Number = Decoder[json.Number]{
+	decode: func(v value, n *json.Number) error {
+		if !v.is(kindNumber) {
+			return kindError("number", v)
+		}
+		*n = json.Number(v.raw())
+		return nil
+	},
+	schema: func(*schemaDefs) map[string]any {
+		return typeSchema("number")
+	},
+}
+
+// bigFloat parses number with precision enough to keep all its digits.
+func bigFloat(n json.Number) (*big.Float, error) {
+	// log2(10) < 4 bits per decimal digit
+	prec := max(uint(len(n))*4, 64)
+	f, _, err := big.ParseFloat(string(n), 10, prec, big.ToNearestEven)
+	if err != nil {
+		return nil, &DecodeError{Err: fmt.Errorf("invalid number: %s", n)}
+	}
+	return f, nil
+}
+
+// BigFloat decodes number with mantissa precision enough for all of its digits.
+var BigFloat = Decoder[*big.Float]{
+	decode: func(v value, res **big.Float) error {
+		var n json.Number
+		if err := Number.decode(v, &n); err != nil {
+			return err
+		}
+
+		f, err := bigFloat(n)
+		if err != nil {
+			return err
+		}
+		*res = f
+		return nil
+	},
+	schema: func(*schemaDefs) map[string]any {
+		return typeSchema("number")
+	},
+}
+
+// BigIntDigits decodes integer having at most digits decimal digits, failing on fractional numbers.
+// The limit keeps numbers like 1e1000000000 from taking much memory and time to expand.
+func BigIntDigits(digits int) Decoder[*big.Int] {
+	return Decoder[*big.Int]{
+		decode: func(v value, res **big.Int) error {
+			var n json.Number
+			if err := Number.decode(v, &n); err != nil {
+				return err
+			}
+
+			i, err := bigInteger(n, digits)
+			if err != nil {
+				return err
+			}
+			*res = i
+			return nil
+		},
+		schema: func(*schemaDefs) map[string]any {
+			return typeSchema("integer")
+		},
+	}
+}
+
+// BigInt decodes integer of up to 10000 digits, failing on fractional numbers.
+var BigInt = BigIntDigits(10000)
+
+// numberPattern matches JSON number.
+const numberPattern = `^-?(0|[1-9][0-9]*)(\.[0-9]+)?([eE][+-]?[0-9]+)?$`
+
+func isDigit(c byte) bool {
+	return '0' <= c && c <= '9'
+}
+
+// numberString decodes string containing JSON number, like "123".
+func numberString[T any](decoder Decoder[T]) Decoder[T] {
+	return Decoder[T]{
+		decode: func(v value, res *T) error {
+			if !v.is(kindString) {
+				return kindError("string", v)
+			}
+
+			// JSON number starts with minus or digit and ends with digit, so no whitespace around
+			s := v.str()
+			if s == "" || s[0] != '-' && !isDigit(s[0]) || !isDigit(s[len(s)-1]) {
+				return &DecodeError{Err: fmt.Errorf("invalid number: %q", s)}
+			}
+			doc, err := parse([]byte(s))
+			if err != nil || doc.nodes[0].kind != kindNumber {
+				return &DecodeError{Err: fmt.Errorf("invalid number: %q", s)}
+			}
+			return decoder.decode(doc.root(v.all), res)
+		},
+		schema: func(*schemaDefs) map[string]any {
+			return map[string]any{"type": "string", "pattern": numberPattern}
+		},
+	}
+}
+
+// IntString and FloatString decode numbers encoded as strings, like "123".
+var (
+	IntString   = numberString(Int)
+	FloatString = numberString(Float)
+)
+
+func integerSchema(signed bool, bits int) map[string]any {
+	res := typeSchema("integer")
+	switch {
+	case !signed && bits < 64:
+		res["minimum"], res["maximum"] = 0, uint64(1)<<bits-1
+	case !signed:
+		res["minimum"] = 0
+	case bits < 64:
+		res["minimum"], res["maximum"] = -int64(1)<<(bits-1), int64(1)<<(bits-1)-1
+	}
+	return res
+}
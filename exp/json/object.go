@@ -0,0 +1,191 @@
+package json
+
+import (
+	"errors"
+	"slices"
+)
+
+// ObjectField decodes single field of object into part of result.
+type ObjectField[T any] struct {
+	name     string
+	required bool
+	// decode decodes value of field, if it is present
+	decode func(value, *T) error
+	// missing sets result part if field is missing
+	missing func(*T)
+	schema  func(*schemaDefs) map[string]any
+}
+
+// Assign makes field of decoder made by Field, Optional or Option, which is set to
+// result part by set. It panics if decoder is not one of those.
+func Assign[T, F any](decoder Decoder[F], set func(*T, F)) ObjectField[T] {
+	f := decoder.field
+	if f == nil {
+		panic("decoder does not decode single object field, make it by Field, Optional or Option")
+	}
+
+	var missing func(*T)
+	if f.missing != nil {
+		missing = func(res *T) {
+			var x F
+			f.missing(&x)
+			set(res, x)
+		}
+	}
+	return ObjectField[T]{
+		name:     f.name,
+		required: f.missing == nil,
+		decode: func(v value, res *T) error {
+			var x F
+			if err := f.decode(v, &x); err != nil {
+				return err
+			}
+			set(res, x)
+			return nil
+		},
+		missing: missing,
+		schema:  f.schema,
+	}
+}
+
+// Required field fails to decode if it is missing.
+func Required[T, F any](name string, decoder Decoder[F], set func(*T, F)) ObjectField[T] {
+	return Assign(decoder.Field(name), set)
+}
+
+// Optional field is set to fallback if it is missing.
+func Optional[T, F any](name string, decoder Decoder[F], fallback F, set func(*T, F)) ObjectField[T] {
+	return Assign(decoder.Optional(name, fallback), set)
+}
+
+// UnknownPolicy tells what to do with object fields not described in ObjectBuilder.
+type UnknownPolicy int
+
+const (
+	UnknownIgnore UnknownPolicy = iota
+	UnknownError
+	unknownCollect
+)
+
+// ObjectBuilder describes object decoder field by field, like Elm's decode pipeline, so
+// there is no limit on number of fields as with Map2..Map8. Fields are made by Required
+// and Optional functions, since methods cannot have type parameters.
+type ObjectBuilder[T any] struct {
+	fields  []ObjectField[T]
+	unknown UnknownPolicy
+	collect func(*T, map[string]any)
+}
+
+func Object[T any]() ObjectBuilder[T] {
+	return ObjectBuilder[T]{}
+}
+
+// Field adds fields to decode.
+func (b ObjectBuilder[T]) Field(fields ...ObjectField[T]) ObjectBuilder[T] {
+	b.fields = slices.Concat(b.fields, fields)
+	return b
+}
+
+// Unknown sets policy on fields not described in builder, they are ignored by default.
+func (b ObjectBuilder[T]) Unknown(policy UnknownPolicy) ObjectBuilder[T] {
+	b.unknown = policy
+	b.collect = nil
+	return b
+}
+
+// CollectUnknown makes fields not described in builder to be passed to set,
+// set is not called if there are no such fields.
+func (b ObjectBuilder[T]) CollectUnknown(set func(*T, map[string]any)) ObjectBuilder[T] {
+	b.unknown = unknownCollect
+	b.collect = set
+	return b
+}
+
+// Decoder makes decoder of object, errors of all fields are accumulated by ParseBytesAll.
+func (b ObjectBuilder[T]) Decoder() Decoder[T] {
+	index := make(map[string]int, len(b.fields))
+	for i, field := range b.fields {
+		index[field.name] = i
+	}
+
+	return Decoder[T]{decode: b.decode(index), schema: b.schema}
+}
+
+// decode goes over object once, finding fields by index of their names.
+func (b ObjectBuilder[T]) decode(index map[string]int) func(value, *T) error {
+	return func(v value, res *T) error {
+		if !v.is(kindObject) {
+			return kindError("object", v)
+		}
+
+		var errs DecodeErrors
+
+		// values of fields, last one is taken if name is repeated
+		values := make([]value, len(b.fields))
+		found := make([]bool, len(b.fields))
+		var unknown map[string]any
+		var unknownErrs DecodeErrors
+		for key, val := range v.members() {
+			if i, ok := lookup(index, key); ok {
+				values[i], found[i] = val, true
+				continue
+			}
+
+			switch b.unknown {
+			case UnknownError:
+				if len(unknownErrs) == 0 || v.all {
+					unknownErrs.add(v, &DecodeError{Err: errors.New("unknown field")}, key.str())
+				}
+			case unknownCollect:
+				if unknown == nil {
+					unknown = map[string]any{}
+				}
+				unknown[key.str()] = val.any()
+			}
+		}
+
+		var t T
+		for i, field := range b.fields {
+			var err error
+			switch {
+			case found[i]:
+				err = field.decode(values[i], &t)
+			case field.required:
+				err = &DecodeError{Err: errors.New("required field is missing")}
+			default:
+				field.missing(&t)
+			}
+			if !errs.add(v, err, field.name) {
+				return errs.err()
+			}
+		}
+
+		errs.collect(v, unknownErrs.err())
+		if len(errs) != 0 {
+			return errs.err()
+		}
+
+		if unknown != nil {
+			b.collect(&t, unknown)
+		}
+		*res = t
+		return nil
+	}
+}
+
+func (b ObjectBuilder[T]) schema(defs *schemaDefs) map[string]any {
+	properties := make(map[string]any, len(b.fields))
+	var required []string
+	for _, field := range b.fields {
+		properties[field.name] = field.schema(defs)
+		if field.required {
+			required = append(required, field.name)
+		}
+	}
+
+	res := objectSchema(properties, required)
+	if b.unknown == UnknownError {
+		res["additionalProperties"] = false
+	}
+	return res
+}
@@ -0,0 +1,344 @@
+package json
+
+import (
+	"math"
+	"strconv"
+	"unicode/utf8"
+)
+
+// SyntaxError is a description of JSON syntax error, like one of encoding/json.
+type SyntaxError struct {
+	msg string
+	// Offset is number of bytes read before error occurred.
+	Offset int64
+}
+
+func (e *SyntaxError) Error() string {
+	return e.msg
+}
+
+// maxNesting is the maximum nesting of arrays and objects, as in encoding/json.
+const maxNesting = 10000
+
+// parser checks document syntax, splitting it into nodes. Nesting is tracked by
+// stack of open containers instead of recursion, so deep documents can't exhaust stack.
+type parser struct {
+	src   []byte
+	pos   int
+	nodes []node
+	// open are indices of arrays and objects not closed yet
+	open []int32
+}
+
+// parse splits document into nodes, checking its syntax.
+func parse(src []byte) (*document, error) {
+	if len(src) > math.MaxInt32 {
+		return nil, &SyntaxError{"document is too large", 0}
+	}
+
+	// most values take several bytes, so capacity is rarely exceeded
+	p := &parser{src: src, nodes: make([]node, 0, len(src)/8+1)}
+	if err := p.parse(); err != nil {
+		return nil, err
+	}
+	return &document{src, p.nodes}, nil
+}
+
+func (p *parser) parse() error {
+values:
+	for {
+		p.skipSpace()
+		if p.pos == len(p.src) {
+			return p.eof()
+		}
+
+		switch c := p.src[p.pos]; {
+		case c == '[' || c == '{':
+			if len(p.open) == maxNesting {
+				return &SyntaxError{"exceeded max depth", int64(p.pos + 1)}
+			}
+
+			k := kindArray
+			if c == '{' {
+				k = kindObject
+			}
+			p.open = append(p.open, int32(len(p.nodes)))
+			p.nodes = append(p.nodes, node{kind: k, start: int32(p.pos)})
+			p.pos++
+
+			p.skipSpace()
+			switch {
+			case p.pos == len(p.src):
+				return p.eof()
+			case k == kindArray && p.src[p.pos] == ']',
+				k == kindObject && p.src[p.pos] == '}':
+				p.pos++
+				p.close()
+			case k == kindObject:
+				if err := p.key(); err != nil {
+					return err
+				}
+				continue values
+			default:
+				continue values
+			}
+		case c == '"':
+			if err := p.string(); err != nil {
+				return err
+			}
+		case c == '-' || isDigit(c):
+			if err := p.number(); err != nil {
+				return err
+			}
+		case c == 't':
+			if err := p.literal("true", kindTrue); err != nil {
+				return err
+			}
+		case c == 'f':
+			if err := p.literal("false", kindFalse); err != nil {
+				return err
+			}
+		case c == 'n':
+			if err := p.literal("null", kindNull); err != nil {
+				return err
+			}
+		default:
+			return p.invalid("looking for beginning of value")
+		}
+
+		// value is complete, close containers ending after it
+		for {
+			p.skipSpace()
+			if len(p.open) == 0 {
+				if p.pos != len(p.src) {
+					return p.invalid("after top-level value")
+				}
+				return nil
+			}
+			if p.pos == len(p.src) {
+				return p.eof()
+			}
+
+			c := p.src[p.pos]
+			if p.nodes[p.open[len(p.open)-1]].kind == kindArray {
+				switch c {
+				case ',':
+					p.pos++
+					continue values
+				case ']':
+					p.pos++
+					p.close()
+				default:
+					return p.invalid("after array element")
+				}
+			} else {
+				switch c {
+				case ',':
+					p.pos++
+					if err := p.key(); err != nil {
+						return err
+					}
+					continue values
+				case '}':
+					p.pos++
+					p.close()
+				default:
+					return p.invalid("after object key:value pair")
+				}
+			}
+		}
+	}
+}
+
+func (p *parser) skipSpace() {
+	for p.pos < len(p.src) {
+		switch p.src[p.pos] {
+		case ' ', '\t', '\r', '\n':
+			p.pos++
+		default:
+			return
+		}
+	}
+}
+
+// close ends innermost open container at current position.
+func (p *parser) close() {
+	i := p.open[len(p.open)-1]
+	p.open = p.open[:len(p.open)-1]
+	p.nodes[i].end = int32(p.pos)
+	p.nodes[i].next = int32(len(p.nodes))
+}
+
+// leaf adds value without contents, which started at start and ends at current position.
+func (p *parser) leaf(k kind, plain bool, start int) {
+	p.nodes = append(p.nodes, node{
+		kind:  k,
+		plain: plain,
+		start: int32(start),
+		end:   int32(p.pos),
+		next:  int32(len(p.nodes) + 1),
+	})
+}
+
+// key parses object key with colon after it.
+func (p *parser) key() error {
+	p.skipSpace()
+	if p.pos == len(p.src) {
+		return p.eof()
+	}
+	if p.src[p.pos] != '"' {
+		return p.invalid("looking for beginning of object key string")
+	}
+	if err := p.string(); err != nil {
+		return err
+	}
+
+	p.skipSpace()
+	if p.pos == len(p.src) {
+		return p.eof()
+	}
+	if p.src[p.pos] != ':' {
+		return p.invalid("after object key")
+	}
+	p.pos++
+	return nil
+}
+
+func (p *parser) string() error {
+	start := p.pos
+	p.pos++
+	plain := true
+	for p.pos < len(p.src) {
+		switch c := p.src[p.pos]; {
+		case c == '"':
+			p.pos++
+			p.leaf(kindString, plain, start)
+			return nil
+		case c == '\\':
+			plain = false
+			p.pos++
+			if p.pos == len(p.src) {
+				return p.eof()
+			}
+
+			switch p.src[p.pos] {
+			case '"', '\\', '/', 'b', 'f', 'n', 'r', 't':
+				p.pos++
+			case 'u':
+				p.pos++
+				for range 4 {
+					if p.pos == len(p.src) {
+						return p.eof()
+					}
+					if !isHex(p.src[p.pos]) {
+						return p.invalid(`in \u hexadecimal character escape`)
+					}
+					p.pos++
+				}
+			default:
+				return p.invalid("in string escape code")
+			}
+		case c < ' ':
+			return p.invalid("in string literal")
+		default:
+			if c >= utf8.RuneSelf {
+				plain = false
+			}
+			p.pos++
+		}
+	}
+	return p.eof()
+}
+
+func (p *parser) number() error {
+	start := p.pos
+	if p.src[p.pos] == '-' {
+		p.pos++
+	}
+
+	switch {
+	case p.pos == len(p.src):
+		return p.eof()
+	case p.src[p.pos] == '0':
+		p.pos++
+	case isDigit(p.src[p.pos]):
+		p.digits()
+	default:
+		return p.invalid("in numeric literal")
+	}
+
+	if p.pos < len(p.src) && p.src[p.pos] == '.' {
+		p.pos++
+		if p.pos == len(p.src) {
+			return p.eof()
+		}
+		if !isDigit(p.src[p.pos]) {
+			return p.invalid("after decimal point in numeric literal")
+		}
+		p.digits()
+	}
+
+	if p.pos < len(p.src) && (p.src[p.pos] == 'e' || p.src[p.pos] == 'E') {
+		p.pos++
+		if p.pos < len(p.src) && (p.src[p.pos] == '+' || p.src[p.pos] == '-') {
+			p.pos++
+		}
+		if p.pos == len(p.src) {
+			return p.eof()
+		}
+		if !isDigit(p.src[p.pos]) {
+			return p.invalid("in exponent of numeric literal")
+		}
+		p.digits()
+	}
+
+	p.leaf(kindNumber, true, start)
+	return nil
+}
+
+func (p *parser) digits() {
+	for p.pos < len(p.src) && isDigit(p.src[p.pos]) {
+		p.pos++
+	}
+}
+
+func (p *parser) literal(word string, k kind) error {
+	start := p.pos
+	for i := range len(word) {
+		if p.pos == len(p.src) {
+			return p.eof()
+		}
+		if p.src[p.pos] != word[i] {
+			return p.invalid("in literal " + word + " (expecting " + quoteChar(word[i]) + ")")
+		}
+		p.pos++
+	}
+	p.leaf(k, true, start)
+	return nil
+}
+
+func (p *parser) eof() error {
+	return &SyntaxError{"unexpected end of JSON input", int64(len(p.src))}
+}
+
+// invalid reports unexpected character at current position.
+func (p *parser) invalid(context string) error {
+	return &SyntaxError{"invalid character " + quoteChar(p.src[p.pos]) + " " + context, int64(p.pos + 1)}
+}
+
+func isHex(c byte) bool {
+	return isDigit(c) || 'a' <= c && c <= 'f' || 'A' <= c && c <= 'F'
+}
+
+// quoteChar formats c as a quoted character literal.
+func quoteChar(c byte) string {
+	switch c {
+	case '\'':
+		return `'\''`
+	case '"':
+		return `'"'`
+	}
+
+	s := strconv.Quote(string(rune(c)))
+	return "'" + s[1:len(s)-1] + "'"
+}
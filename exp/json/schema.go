@@ -0,0 +1,162 @@
+package json
+
+import (
+	"maps"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+const schemaDialect = "https://json-schema.org/draft/2020-12/schema"
+
+// schemaDefs collects schemas of Lazy decoders while schema of decoder is made.
+type schemaDefs struct {
+	// defs are schemas of Lazy decoders, names are their keys
+	defs  map[string]any
+	names map[*lazyID]string
+	// expanding is number of Lazy decoders being defined, one inside another
+	expanding int
+}
+
+// maxLazyExpansions limits nesting of Lazy definitions, which is unbounded
+// for recursive decoders making new Lazy decoder on every use.
+const maxLazyExpansions = 32
+
+// anySchema describes decoders accepting anything or ones which cannot be described.
+func anySchema(*schemaDefs) map[string]any {
+	return map[string]any{}
+}
+
+// Schema describes documents accepted by decoder as JSON Schema draft 2020-12.
+//
+// Checks of Validate, Std and Custom decoders cannot be described, so they are assumed
+// to accept anything, while named constraints of Constrain are. AndThen is described by
+// its first decoder only, since the second one depends on decoded value. Decoders made
+// by Lazy are put into "$defs", named after their types, so recursive types are described
+// by references.
+func Schema[T any](decoder Decoder[T]) map[string]any {
+	defs := &schemaDefs{defs: map[string]any{}, names: map[*lazyID]string{}}
+	res := maps.Clone(decoder.schema(defs))
+	res["$schema"] = schemaDialect
+	if len(defs.defs) > 0 {
+		res["$defs"] = defs.defs
+	}
+	return res
+}
+
+// lazySchema returns reference to definition of lazy decoder with given id,
+// defining it first if needed.
+func lazySchema[T any](defs *schemaDefs, id *lazyID, decoder func() Decoder[T]) map[string]any {
+	name, ok := defs.names[id]
+	if !ok {
+		if defs.expanding == maxLazyExpansions {
+			return map[string]any{}
+		}
+
+		name = defName(reflect.TypeFor[T]().Name())
+		if _, ok := defs.defs[name]; ok {
+			name += "_" + strconv.Itoa(len(defs.defs))
+		}
+
+		defs.names[id] = name
+		defs.defs[name] = map[string]any{} // placeholder for recursive references
+		defs.expanding++
+		defs.defs[name] = decoder().schema(defs)
+		defs.expanding--
+	}
+	return map[string]any{"$ref": "#/$defs/" + name}
+}
+
+// defName makes definition name usable in reference from type name.
+func defName(typeName string) string {
+	name := strings.Map(func(r rune) rune {
+		if r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return r
+		}
+		return '_'
+	}, typeName)
+	if name == "" {
+		return "def"
+	}
+	return name
+}
+
+func typeSchema(kind string) map[string]any {
+	return map[string]any{"type": kind}
+}
+
+func objectSchema(properties map[string]any, required []string) map[string]any {
+	res := map[string]any{"type": "object", "properties": properties}
+	if len(required) > 0 {
+		res["required"] = required
+	}
+	return res
+}
+
+// isPlainObject reports whether schema only lists object properties, so it can be merged.
+func isPlainObject(schema map[string]any) bool {
+	for k := range schema {
+		if k != "type" && k != "properties" && k != "required" {
+			return false
+		}
+	}
+	return schema["type"] == "object"
+}
+
+// allOf describes value accepted by all schemas, merging properties of objects if possible.
+func allOf(schemas ...map[string]any) map[string]any {
+	nonEmpty := make([]map[string]any, 0, len(schemas))
+	for _, s := range schemas {
+		if len(s) != 0 {
+			nonEmpty = append(nonEmpty, s)
+		}
+	}
+
+	switch len(nonEmpty) {
+	case 0:
+		return map[string]any{}
+	case 1:
+		return nonEmpty[0]
+	}
+
+	properties := map[string]any{}
+	var required []string
+	for _, s := range nonEmpty {
+		if !isPlainObject(s) {
+			return map[string]any{"allOf": nonEmpty}
+		}
+
+		for k, v := range s["properties"].(map[string]any) {
+			if _, ok := properties[k]; ok {
+				return map[string]any{"allOf": nonEmpty}
+			}
+			properties[k] = v
+		}
+		if r, ok := s["required"].([]string); ok {
+			required = append(required, r...)
+		}
+	}
+	return objectSchema(properties, required)
+}
+
+func schemasOf[T any](defs *schemaDefs, decoders []Decoder[T]) []map[string]any {
+	res := make([]map[string]any, len(decoders))
+	for i, decoder := range decoders {
+		res[i] = decoder.schema(defs)
+	}
+	return res
+}
+
+func indexSchema[T any](defs *schemaDefs, i int, decoder Decoder[T]) map[string]any {
+	if i < 0 {
+		return map[string]any{"not": map[string]any{}}
+	}
+
+	prefix := make([]map[string]any, i+1)
+	for j := range i {
+		prefix[j] = map[string]any{}
+	}
+	prefix[i] = decoder.schema(defs)
+	return map[string]any{"type": "array", "prefixItems": prefix, "minItems": i + 1}
+}
@@ -0,0 +1,107 @@
+package json
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// Tagged decodes discriminated union, choosing variant decoder by value of string field,
+// like {"type": "circle", "radius": 1}. Variant decoder gets the object without the tag field,
+// so variants rejecting unknown fields work as is.
+func Tagged[T any](field string, variants map[string]Decoder[T]) Decoder[T] {
+	tags := slices.Sorted(maps.Keys(variants))
+	for i, tag := range tags {
+		tags[i] = fmt.Sprintf("%q", tag)
+	}
+	expected := strings.Join(tags, ", ")
+
+	tagDecoder := String.Field(field)
+	return Decoder[T]{
+		decode: func(v value, res *T) error {
+			var tag string
+			if err := tagDecoder.decode(v, &tag); err != nil {
+				return err
+			}
+
+			decoder, ok := variants[tag]
+			if !ok {
+				return withPath(&DecodeError{Err: fmt.Errorf("unknown type %q, expected one of %s", tag, expected)}, field)
+			}
+			return decoder.decode(v.hide(field), res)
+		},
+		schema: func(defs *schemaDefs) map[string]any {
+			schemas := make([]map[string]any, 0, len(variants))
+			for _, tag := range slices.Sorted(maps.Keys(variants)) {
+				schemas = append(schemas, withTag(variants[tag].schema(defs), field, tag))
+			}
+			return map[string]any{"oneOf": schemas}
+		},
+	}
+}
+
+// withTag adds tag field to schema of variant object.
+func withTag(schema map[string]any, field, tag string) map[string]any {
+	tagSchema := objectSchema(map[string]any{field: map[string]any{"const": tag}}, []string{field})
+	properties, ok := schema["properties"].(map[string]any)
+	if schema["type"] != "object" || !ok {
+		return allOf(tagSchema, schema)
+	}
+
+	// variant might forbid additional properties, so tag is put right into its properties
+	res := maps.Clone(schema)
+	res["properties"] = maps.Clone(properties)
+	res["properties"].(map[string]any)[field] = tagSchema["properties"].(map[string]any)[field]
+	required, _ := schema["required"].([]string)
+	res["required"] = append([]string{field}, required...)
+	return res
+}
+
+// Untagged decodes union without discriminator, using first variant which succeeds.
+// If none succeeds, error of the closest variant is returned: variants failed on
+// the value itself, like kind mismatch, are considered farthest, then variants with
+// fewer errors are closer.
+func Untagged[T any](decoders ...Decoder[T]) Decoder[T] {
+	return Decoder[T]{
+		decode: func(v value, res *T) error {
+			// variants are compared by number of errors, so all of them are collected
+			all := v
+			all.all = true
+
+			var closest DecodeErrors
+			for _, decoder := range decoders {
+				var t T
+				err := decoder.decode(all, &t)
+				if err == nil {
+					*res = t
+					return nil
+				}
+
+				if errs := decodeErrors(err); closest == nil || closer(errs, closest) {
+					closest = errs
+				}
+			}
+			if closest == nil {
+				return &DecodeError{Err: errors.New("no variants")}
+			}
+			return closest.err()
+		},
+		schema: func(defs *schemaDefs) map[string]any {
+			return map[string]any{"anyOf": schemasOf(defs, decoders)}
+		},
+	}
+}
+
+// closer reports whether variant failed with errs went further than one failed with other.
+func closer(errs, other DecodeErrors) bool {
+	atRoot := func(errs DecodeErrors) bool {
+		return slices.ContainsFunc(errs, func(e *DecodeError) bool { return e.Path == "" })
+	}
+
+	if a, b := atRoot(errs), atRoot(other); a != b {
+		return b
+	}
+	return len(errs) < len(other)
+}
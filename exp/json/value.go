@@ -0,0 +1,309 @@
+package json
+
+import (
+	"encoding/json"
+	"iter"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+type kind uint8
+
+const (
+	kindNull kind = iota
+	kindFalse
+	kindTrue
+	kindNumber
+	kindString
+	kindArray
+	kindObject
+)
+
+// kindNames are JSON kinds as reported in errors.
+var kindNames = [...]string{
+	kindNull:   "null",
+	kindFalse:  "boolean",
+	kindTrue:   "boolean",
+	kindNumber: "number",
+	kindString: "string",
+	kindArray:  "array",
+	kindObject: "object",
+}
+
+// node is a value in document. Children of array follow it, children of object
+// follow it as key string and value pairs.
+type node struct {
+	kind kind
+	// plain string has neither escapes nor non-ASCII characters, so it is taken as is
+	plain bool
+	// start and end are offsets of value text, including quotes and brackets
+	start, end int32
+	// next is index of node after the value and all its children
+	next int32
+}
+
+// document is parsed JSON text, values refer to it instead of being copied out.
+type document struct {
+	src   []byte
+	nodes []node
+}
+
+// value is a JSON value, decoders run over it.
+type value struct {
+	doc *document
+	i   int32
+	// hidden are object fields decoders should not see, like tag of Tagged
+	hidden *hidden
+	// all tells to collect all errors instead of stopping at the first one
+	all bool
+}
+
+type hidden struct {
+	name string
+	next *hidden
+}
+
+func (doc *document) root(all bool) value {
+	return value{doc, 0, nil, all}
+}
+
+// at returns value of node i inside v.
+func (v value) at(i int32) value {
+	return value{v.doc, i, nil, v.all}
+}
+
+func (v value) node() node {
+	return v.doc.nodes[v.i]
+}
+
+func (v value) is(k kind) bool {
+	return v.node().kind == k
+}
+
+func (v value) kind() string {
+	return kindNames[v.node().kind]
+}
+
+// raw returns text of value as it is written in document.
+func (v value) raw() []byte {
+	n := v.node()
+	return v.doc.src[n.start:n.end]
+}
+
+// str returns contents of string value.
+func (v value) str() string {
+	n := v.node()
+	b := v.doc.src[n.start+1 : n.end-1]
+	if n.plain {
+		return string(b)
+	}
+	return string(unquote(b))
+}
+
+// equal reports whether string value is name, without allocating for plain strings.
+func (v value) equal(name string) bool {
+	n := v.node()
+	b := v.doc.src[n.start+1 : n.end-1]
+	if n.plain {
+		return string(b) == name
+	}
+	return string(unquote(b)) == name
+}
+
+// lookup finds string value in m, without allocating for plain strings.
+func lookup[V any](m map[string]V, v value) (V, bool) {
+	n := v.node()
+	b := v.doc.src[n.start+1 : n.end-1]
+	if !n.plain {
+		b = unquote(b)
+	}
+	res, ok := m[string(b)]
+	return res, ok
+}
+
+// elems iterates over elements of array.
+func (v value) elems() iter.Seq2[int, value] {
+	return func(yield func(int, value) bool) {
+		end := v.node().next
+		for i, j := 0, v.i+1; j < end; i, j = i+1, v.doc.nodes[j].next {
+			if !yield(i, v.at(j)) {
+				return
+			}
+		}
+	}
+}
+
+// len returns number of elements of array.
+func (v value) len() int {
+	n := 0
+	for range v.elems() {
+		n++
+	}
+	return n
+}
+
+// members iterates over keys and values of object, in order of document.
+func (v value) members() iter.Seq2[value, value] {
+	return func(yield func(value, value) bool) {
+		end := v.node().next
+		for k := v.i + 1; k < end; {
+			key := v.at(k)
+			val := v.at(v.doc.nodes[k].next)
+			k = v.doc.nodes[val.i].next
+			if v.isHidden(key) {
+				continue
+			}
+			if !yield(key, val) {
+				return
+			}
+		}
+	}
+}
+
+func (v value) isHidden(key value) bool {
+	for h := v.hidden; h != nil; h = h.next {
+		if key.equal(h.name) {
+			return true
+		}
+	}
+	return false
+}
+
+// field finds value of object field. If name is repeated, the last one is taken,
+// as encoding/json does.
+func (v value) field(name string) (value, bool) {
+	var res value
+	found := false
+	for key, val := range v.members() {
+		if key.equal(name) {
+			res, found = val, true
+		}
+	}
+	return res, found
+}
+
+// hide makes object field invisible to decoders.
+func (v value) hide(name string) value {
+	v.hidden = &hidden{name, v.hidden}
+	return v
+}
+
+// withinDepth checks nesting of value. Depth of scalar is 0, of [] is 1.
+func (v value) withinDepth(depth int) bool {
+	// ends of containers enclosing current node
+	var open []int32
+	for j := v.i; j < v.node().next; j++ {
+		for len(open) > 0 && open[len(open)-1] <= j {
+			open = open[:len(open)-1]
+		}
+
+		if n := v.doc.nodes[j]; n.kind == kindArray || n.kind == kindObject {
+			open = append(open, n.next)
+			if len(open) > depth {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// any converts value to one encoding/json decodes into any, with numbers as json.Number.
+func (v value) any() any {
+	switch n := v.node(); n.kind {
+	case kindNull:
+		return nil
+	case kindFalse:
+		return false
+	case kindTrue:
+		return true
+	case kindNumber:
+		return json.Number(v.raw())
+	case kindString:
+		return v.str()
+	case kindArray:
+		res := make([]any, 0, v.len())
+		for _, elem := range v.elems() {
+			res = append(res, elem.any())
+		}
+		return res
+	default:
+		res := map[string]any{}
+		for key, val := range v.members() {
+			res[key.str()] = val.any()
+		}
+		return res
+	}
+}
+
+// unquote decodes string contents with escapes, replacing invalid UTF-8 and
+// unpaired surrogates with U+FFFD, as encoding/json does.
+func unquote(b []byte) []byte {
+	res := make([]byte, 0, len(b))
+	for i := 0; i < len(b); {
+		c := b[i]
+		switch {
+		case c == '\\':
+			switch c := b[i+1]; c {
+			case 'b':
+				res = append(res, '\b')
+			case 'f':
+				res = append(res, '\f')
+			case 'n':
+				res = append(res, '\n')
+			case 'r':
+				res = append(res, '\r')
+			case 't':
+				res = append(res, '\t')
+			case 'u':
+				r := hex4(b[i+2:])
+				i += 6
+				if utf16.IsSurrogate(r) {
+					// the second half is taken only if it makes valid pair with the first
+					if i+6 <= len(b) && b[i] == '\\' && b[i+1] == 'u' {
+						if pair := utf16.DecodeRune(r, hex4(b[i+2:])); pair != utf8.RuneError {
+							r = pair
+							i += 6
+						}
+					}
+					if utf16.IsSurrogate(r) {
+						r = utf8.RuneError
+					}
+				}
+				res = utf8.AppendRune(res, r)
+				continue
+			default: // quote, backslash or slash
+				res = append(res, c)
+			}
+			i += 2
+		case c < utf8.RuneSelf:
+			res = append(res, c)
+			i++
+		default:
+			r, size := utf8.DecodeRune(b[i:])
+			if r == utf8.RuneError && size == 1 {
+				res = utf8.AppendRune(res, r)
+			} else {
+				res = append(res, b[i:i+size]...)
+			}
+			i += size
+		}
+	}
+	return res
+}
+
+// hex4 decodes four hexadecimal digits checked by parser.
+func hex4(b []byte) rune {
+	var r rune
+	for _, c := range b[:4] {
+		switch {
+		case c <= '9':
+			c -= '0'
+		case c <= 'F':
+			c -= 'A' - 10
+		default:
+			c -= 'a' - 10
+		}
+		r = r<<4 | rune(c)
+	}
+	return r
+}
@@ -1,5 +1,7 @@
 package zun
 
+import "math/rand/v2"
+
 // len(res) >= len(slice)
 func Map[R, T any](
 	res []R,
@@ -239,3 +241,11 @@ func Fold[T, R any](op func(T, R) R, initial R, slice ...T) R {
 	}
 	return res
 }
+
+// Shuffle shuffles slice in place using Fisher-Yates algorithm.
+func Shuffle[T any](xs []T, rng *rand.Rand) {
+	for i := len(xs) - 1; i > 0; i-- {
+		j := rng.IntN(i + 1)
+		xs[i], xs[j] = xs[j], xs[i]
+	}
+}
@@ -0,0 +1,145 @@
+package iter
+
+// functions to compare Seqs
+
+import (
+	"cmp"
+	"fmt"
+	"iter"
+
+	"github.com/rprtr258/fun"
+)
+
+// Mismatch describes first position at which two streams differ.
+// Value is invalid if corresponding stream ended before that position.
+type Mismatch[V any] struct {
+	Index int
+	X, Y  fun.Option[V]
+}
+
+func (m Mismatch[V]) String() string {
+	return fmt.Sprintf("streams differ at index %d: %v != %v", m.Index, m.X, m.Y)
+}
+
+// DiffFunc finds first position at which streams differ, using eq to compare elements.
+// Both streams are stopped on first mismatch.
+func DiffFunc[V any](x, y Seq[V], eq func(V, V) bool) (Mismatch[V], bool) {
+	next, stop := y.Pull()
+	defer stop()
+
+	var (
+		mismatch Mismatch[V]
+		found    bool
+	)
+	i := 0
+	x(func(vx V) bool {
+		vy, ok := next()
+		if !ok || !eq(vx, vy) {
+			mismatch = Mismatch[V]{i, fun.Valid(vx), fun.Optional(vy, ok)}
+			found = true
+			return false
+		}
+		i++
+		return true
+	})
+	if found {
+		return mismatch, true
+	}
+
+	if vy, ok := next(); ok {
+		return Mismatch[V]{i, fun.Invalid[V](), fun.Valid(vy)}, true
+	}
+	return Mismatch[V]{}, false
+}
+
+// Diff finds first position at which streams differ.
+// Both streams are stopped on first mismatch.
+func Diff[V comparable](x, y Seq[V]) (Mismatch[V], bool) {
+	return DiffFunc(x, y, func(a, b V) bool { return a == b })
+}
+
+// EqualFunc reports whether streams have the same length and equal elements, using eq to compare elements.
+func EqualFunc[V any](x, y Seq[V], eq func(V, V) bool) bool {
+	_, differ := DiffFunc(x, y, eq)
+	return !differ
+}
+
+// Equal reports whether streams have the same length and equal elements.
+func Equal[V comparable](x, y Seq[V]) bool {
+	_, differ := Diff(x, y)
+	return !differ
+}
+
+// Equal2 reports whether streams of pairs have the same length and equal elements.
+func Equal2[K, V comparable](x, y Seq2[K, V]) bool {
+	next, stop := iter.Pull2(iter.Seq2[K, V](y))
+	defer stop()
+
+	equal := true
+	x(func(kx K, vx V) bool {
+		ky, vy, ok := next()
+		equal = ok && kx == ky && vx == vy
+		return equal
+	})
+	if !equal {
+		return false
+	}
+
+	_, _, ok := next()
+	return !ok
+}
+
+// CompareFunc compares streams lexicographically, using cmp to compare elements.
+// The result is the first non-zero result of cmp, or if one stream is a prefix
+// of the other, shorter stream is less.
+func CompareFunc[V any](x, y Seq[V], cmp func(V, V) int) int {
+	next, stop := y.Pull()
+	defer stop()
+
+	res := 0
+	x(func(vx V) bool {
+		vy, ok := next()
+		if !ok {
+			res = 1
+			return false
+		}
+		res = cmp(vx, vy)
+		return res == 0
+	})
+	if res != 0 {
+		return res
+	}
+
+	if _, ok := next(); ok {
+		return -1
+	}
+	return 0
+}
+
+// Compare compares streams lexicographically, like slices.Compare does.
+func Compare[V cmp.Ordered](x, y Seq[V]) int {
+	return CompareFunc(x, y, cmp.Compare[V])
+}
+
+// IsSortedFunc reports whether stream is sorted in ascending order, using cmp to compare elements.
+func IsSortedFunc[V any](seq Seq[V], cmp func(V, V) int) bool {
+	var (
+		prev    V
+		hasPrev bool
+	)
+	sorted := true
+	seq(func(v V) bool {
+		if hasPrev && cmp(prev, v) > 0 {
+			sorted = false
+			return false
+		}
+		prev, hasPrev = v, true
+		return true
+	})
+	return sorted
+}
+
+// IsSorted reports whether stream is sorted in ascending order.
+func IsSorted[V cmp.Ordered](seq Seq[V]) bool {
+	return IsSortedFunc(seq, cmp.Compare[V])
+}
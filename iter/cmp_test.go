@@ -0,0 +1,82 @@
+package iter_test
+
+import (
+	"testing"
+
+	"github.com/rprtr258/assert"
+	"github.com/rprtr258/fun"
+
+	"github.com/rprtr258/fun/iter"
+)
+
+func TestEqual(t *testing.T) {
+	t.Parallel()
+
+	assert.True(t, iter.Equal(nats10, nats.Take(10)))
+	assert.True(t, iter.Equal(iter.FromNothing[int](), iter.FromNothing[int]()))
+	assert.False(t, iter.Equal(nats10, nats.Take(9)))
+	assert.False(t, iter.Equal(nats.Take(9), nats10))
+	assert.False(t, iter.Equal(nats10, nats.Skip(1).Take(10)))
+	// infinite streams are stopped on first mismatch
+	assert.False(t, iter.Equal(nats, nats.Skip(1)))
+}
+
+func TestEqual2(t *testing.T) {
+	t.Parallel()
+
+	pairs := func(xs ...int) iter.Seq2[int, int] {
+		return iter.MapTo2(iter.FromMany(xs...), func(x int) (int, int) { return x, x * x })
+	}
+	assert.True(t, iter.Equal2(pairs(1, 2, 3), pairs(1, 2, 3)))
+	assert.False(t, iter.Equal2(pairs(1, 2, 3), pairs(1, 2)))
+	assert.False(t, iter.Equal2(pairs(1, 2), pairs(1, 2, 3)))
+	assert.False(t, iter.Equal2(pairs(1, 2, 3), pairs(1, 3, 3)))
+}
+
+func TestDiff(t *testing.T) {
+	t.Parallel()
+
+	_, differ := iter.Diff(nats10, nats.Take(10))
+	assert.False(t, differ)
+
+	m, differ := iter.Diff(iter.FromMany(1, 2, 3), iter.FromMany(1, 5, 3))
+	assert.True(t, differ)
+	assert.Equal(t, iter.Mismatch[int]{1, fun.Valid(2), fun.Valid(5)}, m)
+	assert.Equal(t, "streams differ at index 1: Some(2) != Some(5)", m.String())
+
+	m, differ = iter.Diff(iter.FromMany(1, 2), iter.FromMany(1, 2, 3))
+	assert.True(t, differ)
+	assert.Equal(t, iter.Mismatch[int]{2, fun.Invalid[int](), fun.Valid(3)}, m)
+
+	m, differ = iter.Diff(iter.FromMany(1, 2, 3), iter.FromMany(1))
+	assert.True(t, differ)
+	assert.Equal(t, iter.Mismatch[int]{1, fun.Valid(2), fun.Invalid[int]()}, m)
+}
+
+func TestCompare(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		x, y []int
+		want int
+	}{
+		{nil, nil, 0},
+		{[]int{1, 2}, []int{1, 2}, 0},
+		{[]int{1, 2}, []int{1, 3}, -1},
+		{[]int{1, 3}, []int{1, 2}, 1},
+		{[]int{1}, []int{1, 2}, -1},
+		{[]int{1, 2}, []int{1}, 1},
+	} {
+		assert.Equal(t, test.want, iter.Compare(iter.FromMany(test.x...), iter.FromMany(test.y...)))
+	}
+}
+
+func TestIsSorted(t *testing.T) {
+	t.Parallel()
+
+	assert.True(t, iter.IsSorted(nats10))
+	assert.True(t, iter.IsSorted(iter.FromNothing[int]()))
+	assert.True(t, iter.IsSorted(iter.FromMany(1, 1, 2)))
+	assert.False(t, iter.IsSorted(iter.FromMany(1, 3, 2)))
+	assert.False(t, iter.IsSorted(iter.Concat(iter.FromMany(1, 0), nats)))
+}
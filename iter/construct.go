@@ -3,8 +3,17 @@ package iter
 // functions to make Iter from something that is not Iter
 
 import (
+	"bufio"
 	"cmp"
+	"database/sql"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/fs"
 	"iter"
+
+	"github.com/rprtr258/fun"
 )
 
 func FromInt(n int) Seq[int] {
@@ -83,3 +92,112 @@ func FromInfiniteGenerator[T any](f func(func(T))) Seq[T] {
 		})
 	}
 }
+
+// FromScanner makes stream of tokens read by scanner.
+// Scanning error, if any, is yielded as the last element.
+func FromScanner(s *bufio.Scanner) Seq2[string, error] {
+	return func(yield func(string, error) bool) {
+		for s.Scan() {
+			if !yield(s.Text(), nil) {
+				return
+			}
+		}
+
+		if err := s.Err(); err != nil {
+			yield("", err)
+		}
+	}
+}
+
+// FromCSV makes stream of records read by csv reader.
+// Reading error, if any, is yielded as the last element.
+func FromCSV(r *csv.Reader) Seq2[[]string, error] {
+	return func(yield func([]string, error) bool) {
+		for {
+			record, err := r.Read()
+			if errors.Is(err, io.EOF) {
+				return
+			}
+
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+
+			if !yield(record, nil) {
+				return
+			}
+		}
+	}
+}
+
+// FromJSONDecoder makes stream of consecutive json values read by decoder.
+// Decoding error, if any, is yielded as the last element.
+func FromJSONDecoder[T any](d *json.Decoder) Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		for {
+			var t T
+			err := d.Decode(&t)
+			if errors.Is(err, io.EOF) {
+				return
+			}
+
+			if err != nil {
+				yield(t, err)
+				return
+			}
+
+			if !yield(t, nil) {
+				return
+			}
+		}
+	}
+}
+
+// WalkFS makes stream of paths and entries of file tree rooted at root, in lexical order.
+// If prune is not nil and returns true for a directory, its contents are skipped.
+// Errors met while walking are yielded along with path they happened on, walking then continues.
+func WalkFS(
+	fsys fs.FS,
+	root string,
+	prune func(path string, d fs.DirEntry) bool,
+) Seq2[fun.Pair[string, fs.DirEntry], error] {
+	return func(yield func(fun.Pair[string, fs.DirEntry], error) bool) {
+		_ = fs.WalkDir(fsys, root, func(path string, d fs.DirEntry, err error) error {
+			if !yield(fun.Pair[string, fs.DirEntry]{K: path, V: d}, err) {
+				return fs.SkipAll
+			}
+
+			if err == nil && d.IsDir() && prune != nil && prune(path, d) {
+				return fs.SkipDir
+			}
+			return nil
+		})
+	}
+}
+
+// FromSQLRows makes stream of rows converted using scan function.
+// Rows are closed when stream ends, including early break.
+// Scan or iteration error, if any, is yielded as the last element.
+func FromSQLRows[T any](rows *sql.Rows, scan func(*sql.Rows) (T, error)) Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		defer rows.Close()
+
+		for rows.Next() {
+			t, err := scan(rows)
+			if err != nil {
+				yield(t, err)
+				return
+			}
+
+			if !yield(t, nil) {
+				return
+			}
+		}
+
+		if err := rows.Err(); err != nil {
+			var zero T
+			yield(zero, err)
+		}
+	}
+}
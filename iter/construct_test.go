@@ -0,0 +1,230 @@
+package iter_test
+
+import (
+	"bufio"
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/fs"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/rprtr258/assert"
+	"github.com/rprtr258/fun"
+
+	"github.com/rprtr258/fun/iter"
+)
+
+func collect2[K any](t *testing.T, seq iter.Seq2[K, error]) []K {
+	t.Helper()
+	res := []K{}
+	for k, err := range seq {
+		assert.NoError(t, err)
+		res = append(res, k)
+	}
+	return res
+}
+
+func TestFromScanner(t *testing.T) {
+	t.Parallel()
+
+	s := bufio.NewScanner(strings.NewReader("a b\nc"))
+	s.Split(bufio.ScanWords)
+	assert.Equal(t, []string{"a", "b", "c"}, collect2(t, iter.FromScanner(s)))
+}
+
+type failingReader struct{}
+
+var errRead = errors.New("read failed")
+
+func (failingReader) Read([]byte) (int, error) {
+	return 0, errRead
+}
+
+func TestFromScannerError(t *testing.T) {
+	t.Parallel()
+
+	var gotErr error
+	for _, err := range iter.FromScanner(bufio.NewScanner(failingReader{})) {
+		gotErr = err
+	}
+	assert.Assert(t, errors.Is(gotErr, errRead))
+}
+
+func TestFromCSV(t *testing.T) {
+	t.Parallel()
+
+	r := csv.NewReader(strings.NewReader("a,b\n\"c\nd\",e\n"))
+	assert.Equal(t, [][]string{{"a", "b"}, {"c\nd", "e"}}, collect2(t, iter.FromCSV(r)))
+}
+
+func TestFromJSONDecoder(t *testing.T) {
+	t.Parallel()
+
+	d := json.NewDecoder(strings.NewReader(`1 2 3`))
+	assert.Equal(t, []int{1, 2, 3}, collect2(t, iter.FromJSONDecoder[int](d)))
+
+	errs := 0
+	for _, err := range iter.FromJSONDecoder[int](json.NewDecoder(strings.NewReader(`1 "a"`))) {
+		if err != nil {
+			errs++
+		}
+	}
+	assert.Equal(t, 1, errs)
+}
+
+// fakeRows is driver.Rows yielding single int column, optionally failing after all values.
+type fakeRows struct {
+	values []int64
+	err    error
+	closed bool
+}
+
+func (r *fakeRows) Columns() []string { return []string{"n"} }
+
+func (r *fakeRows) Close() error {
+	r.closed = true
+	return nil
+}
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if len(r.values) == 0 {
+		if r.err != nil {
+			return r.err
+		}
+		return io.EOF
+	}
+	dest[0], r.values = r.values[0], r.values[1:]
+	return nil
+}
+
+// fakeConn serves the same rows for any query.
+type fakeConn struct{ rows *fakeRows }
+
+func (c fakeConn) Prepare(string) (driver.Stmt, error) { return fakeStmt(c), nil }
+func (fakeConn) Close() error                          { return nil }
+func (fakeConn) Begin() (driver.Tx, error)             { return nil, errors.ErrUnsupported }
+
+type fakeStmt fakeConn
+
+func (fakeStmt) Close() error  { return nil }
+func (fakeStmt) NumInput() int { return -1 }
+
+func (fakeStmt) Exec([]driver.Value) (driver.Result, error) {
+	return nil, errors.ErrUnsupported
+}
+
+func (s fakeStmt) Query([]driver.Value) (driver.Rows, error) { return s.rows, nil }
+
+func (c fakeConn) Connect(context.Context) (driver.Conn, error) { return c, nil }
+func (fakeConn) Driver() driver.Driver                          { return nil }
+
+func queryFake(t *testing.T, rows *fakeRows) *sql.Rows {
+	t.Helper()
+	db := sql.OpenDB(fakeConn{rows})
+	t.Cleanup(func() { db.Close() })
+	res, err := db.Query("SELECT n")
+	assert.NoError(t, err)
+	return res
+}
+
+func scanInt(rows *sql.Rows) (int, error) {
+	var n int
+	err := rows.Scan(&n)
+	return n, err
+}
+
+func TestFromSQLRows(t *testing.T) {
+	t.Parallel()
+
+	t.Run("all", func(t *testing.T) {
+		t.Parallel()
+
+		rows := &fakeRows{values: []int64{1, 2, 3}}
+		assert.Equal(t, []int{1, 2, 3}, collect2(t, iter.FromSQLRows(queryFake(t, rows), scanInt)))
+		assert.True(t, rows.closed)
+	})
+
+	t.Run("break", func(t *testing.T) {
+		t.Parallel()
+
+		rows := &fakeRows{values: []int64{1, 2, 3}}
+		got := []int{}
+		for n, err := range iter.FromSQLRows(queryFake(t, rows), scanInt) {
+			assert.NoError(t, err)
+			got = append(got, n)
+			break
+		}
+		assert.Equal(t, []int{1}, got)
+		assert.True(t, rows.closed)
+	})
+
+	t.Run("scan error", func(t *testing.T) {
+		t.Parallel()
+
+		errScan := errors.New("scan failed")
+		rows := &fakeRows{values: []int64{1, 2}}
+		var errs []error
+		for _, err := range iter.FromSQLRows(queryFake(t, rows), func(*sql.Rows) (int, error) {
+			return 0, errScan
+		}) {
+			errs = append(errs, err)
+		}
+		assert.Equal(t, []error{errScan}, errs)
+		assert.True(t, rows.closed)
+	})
+
+	t.Run("rows error", func(t *testing.T) {
+		t.Parallel()
+
+		errNext := errors.New("connection lost")
+		rows := &fakeRows{values: []int64{1}, err: errNext}
+		got, errs := []int{}, []error{}
+		for n, err := range iter.FromSQLRows(queryFake(t, rows), scanInt) {
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			got = append(got, n)
+		}
+		assert.Equal(t, []int{1}, got)
+		assert.Equal(t, []error{errNext}, errs)
+	})
+}
+
+func TestWalkFS(t *testing.T) {
+	t.Parallel()
+
+	fsys := fstest.MapFS{
+		"a/x.txt":        {},
+		"b/y.txt":        {},
+		"b/skip/z.txt":   {},
+		"b/nested/w.txt": {},
+	}
+	paths := iter.MapFrom2(
+		iter.WalkFS(fsys, ".", func(path string, _ fs.DirEntry) bool {
+			return path == "b/skip"
+		}),
+		func(p fun.Pair[string, fs.DirEntry], err error) string {
+			assert.NoError(t, err)
+			return p.K
+		},
+	).Slice()
+	assert.Equal(t, []string{
+		".",
+		"a",
+		"a/x.txt",
+		"b",
+		"b/nested",
+		"b/nested/w.txt",
+		"b/skip",
+		"b/y.txt",
+	}, paths)
+
+	assert.Equal(t, 2, iter.WalkFS(fsys, ".", nil).Keys().Take(2).Count())
+}
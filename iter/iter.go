@@ -87,6 +87,71 @@ func Concat[V any](seqs ...Seq[V]) Seq[V] {
 	}
 }
 
+// Interleave returns an iterator taking one element from each of the sequences in turn.
+// Exhausted sequences are skipped, iteration ends when all sequences are exhausted.
+func Interleave[V any](seqs ...Seq[V]) Seq[V] {
+	return WeightedInterleave(slices.Repeat([]int{1}, len(seqs)), seqs...)
+}
+
+// WeightedInterleave returns an iterator mixing sequences in proportion to their weights.
+// Elements are picked using smooth weighted round-robin, so sequences are interleaved evenly,
+// e.g. weights 2 and 1 give x, y, x, x, y, x, ... order. Sequences with zero weight are not used.
+// Exhausted sequences are skipped, iteration ends when all sequences are exhausted.
+func WeightedInterleave[V any](weights []int, seqs ...Seq[V]) Seq[V] {
+	if len(weights) != len(seqs) {
+		panic(fmt.Sprintf("Weights count must be equal to sequences count, but %d and %d given", len(weights), len(seqs)))
+	}
+	for _, w := range weights {
+		if w < 0 {
+			panic(fmt.Sprintf("Weights must be non-negative, but %d given", w))
+		}
+	}
+
+	return func(yield func(V) bool) {
+		nexts := make([]func() (V, bool), len(seqs))
+		for i, seq := range seqs {
+			if weights[i] == 0 {
+				continue
+			}
+
+			next, stop := seq.Pull()
+			defer stop()
+			nexts[i] = next
+		}
+
+		total := 0
+		for _, w := range weights {
+			total += w
+		}
+		current := make([]int, len(seqs))
+		for total > 0 {
+			best := -1
+			for i, next := range nexts {
+				if next == nil {
+					continue
+				}
+
+				current[i] += weights[i]
+				if best == -1 || current[i] > current[best] {
+					best = i
+				}
+			}
+			current[best] -= total
+
+			v, ok := nexts[best]()
+			if !ok {
+				nexts[best] = nil
+				total -= weights[best]
+				continue
+			}
+
+			if !yield(v) {
+				return
+			}
+		}
+	}
+}
+
 // MergeFunc merges two sequences of values ordered by the function f.
 // Values appear in the output once for each time they appear in x
 // and once for each time they appear in y.
@@ -100,15 +165,21 @@ func (x Seq[V]) MergeFunc(y Seq[V], f func(V, V) int) Seq[V] {
 		next, stop := y.Pull()
 		defer stop()
 		vy, ok := next()
+		stopped := false
 		x(func(vx V) bool {
 			for ok && f(vx, vy) > 0 {
 				if !yield(vy) {
+					stopped = true
 					return false
 				}
 				vy, ok = next()
 			}
-			return yield(vx)
+			stopped = !yield(vx)
+			return !stopped
 		})
+		if stopped {
+			return
+		}
 
 		for ; ok; vy, ok = next() {
 			if !yield(vy) {
@@ -312,3 +312,27 @@ func TestCount_lenToSlice(t *testing.T) {
 		assert.Equal(t, seq1.Count(), len(seq2.Slice()))
 	}
 }
+
+func TestInterleave(t *testing.T) {
+	t.Parallel()
+
+	assertStream(t, iter.Interleave(
+		iter.FromMany(1, 4, 6),
+		iter.FromMany(2),
+		iter.FromMany(3, 5),
+	), []int{1, 2, 3, 4, 5, 6})
+	assertStream(t, iter.Interleave[int](), nil)
+	assertStream(t, iter.Interleave(nats, nats).Take(4), []int{0, 0, 1, 1})
+}
+
+func TestWeightedInterleave(t *testing.T) {
+	t.Parallel()
+
+	assertStream(t, iter.WeightedInterleave(
+		[]int{2, 1, 0},
+		iter.FromMany(0, 0, 0, 0, 0, 0),
+		iter.FromMany(1, 1),
+		iter.FromMany(2),
+	), []int{0, 1, 0, 0, 1, 0, 0, 0})
+	assertStream(t, iter.WeightedInterleave([]int{3, 1}, nats, nats).Take(8), []int{0, 1, 0, 2, 3, 4, 1, 5})
+}
@@ -0,0 +1,125 @@
+package iter
+
+// functions to sample and shuffle Seq using explicitly passed random source
+
+import (
+	"container/heap"
+	"fmt"
+	"math"
+	"math/rand/v2"
+
+	"github.com/rprtr258/fun/exp/zun"
+)
+
+// ReservoirSample consumes the seq and returns uniformly chosen sample of at most k elements.
+// Order of the elements in the sample is not specified.
+func ReservoirSample[V any](seq Seq[V], k int, rng *rand.Rand) []V {
+	if k < 0 {
+		panic(fmt.Sprintf("Sample size must be non-negative, but %d given", k))
+	}
+
+	res := make([]V, 0, k)
+	seen := 0
+	seq(func(v V) bool {
+		seen++
+		if len(res) < k {
+			res = append(res, v)
+		} else if j := rng.IntN(seen); j < k {
+			res[j] = v
+		}
+		return true
+	})
+	return res
+}
+
+// BernoulliSample leaves every element of the seq with probability p independently.
+func BernoulliSample[V any](seq Seq[V], p float64, rng *rand.Rand) Seq[V] {
+	if p < 0 || p > 1 {
+		panic(fmt.Sprintf("Probability must be in [0, 1], but %v given", p))
+	}
+
+	return func(yield func(V) bool) {
+		seq(func(v V) bool {
+			return rng.Float64() >= p || yield(v)
+		})
+	}
+}
+
+type weightedItem[V any] struct {
+	value V
+	key   float64
+}
+
+// weightedHeap is min-heap of items by key.
+type weightedHeap[V any] []weightedItem[V]
+
+func (h weightedHeap[V]) Len() int           { return len(h) }
+func (h weightedHeap[V]) Less(i, j int) bool { return h[i].key < h[j].key }
+func (h weightedHeap[V]) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+func (h *weightedHeap[V]) Push(x any)        { *h = append(*h, x.(weightedItem[V])) }
+func (h *weightedHeap[V]) Pop() any {
+	old := *h
+	x := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return x
+}
+
+// WeightedReservoirSample consumes the seq and returns sample of at most k elements
+// without replacement, where each element is chosen with probability proportional to its weight.
+// Elements with non-positive weight are never chosen. Order of the elements in the sample is not specified.
+// Algorithm A-Res by Efraimidis and Spirakis is used.
+func WeightedReservoirSample[V any](seq Seq[V], k int, weight func(V) float64, rng *rand.Rand) []V {
+	if k < 0 {
+		panic(fmt.Sprintf("Sample size must be non-negative, but %d given", k))
+	}
+
+	h := make(weightedHeap[V], 0, k)
+	seq(func(v V) bool {
+		w := weight(v)
+		if w <= 0 || k == 0 {
+			return true
+		}
+
+		// log(u)/w is monotonic with u^(1/w), but does not underflow for small weights
+		key := math.Log(1-rng.Float64()) / w
+		switch {
+		case len(h) < k:
+			heap.Push(&h, weightedItem[V]{v, key})
+		case key > h[0].key:
+			h[0] = weightedItem[V]{v, key}
+			heap.Fix(&h, 0)
+		}
+		return true
+	})
+
+	res := make([]V, len(h))
+	for i, item := range h {
+		res[i] = item.value
+	}
+	return res
+}
+
+// ShuffleSlice returns stream of slice elements in random order.
+// Slice itself is not modified. Shuffling is done lazily, so taking
+// first few elements does not require shuffling whole slice.
+func ShuffleSlice[V any](xs []V, rng *rand.Rand) Seq[V] {
+	return func(yield func(V) bool) {
+		ys := append([]V(nil), xs...)
+		for i := range ys {
+			j := i + rng.IntN(len(ys)-i)
+			ys[i], ys[j] = ys[j], ys[i]
+			if !yield(ys[i]) {
+				return
+			}
+		}
+	}
+}
+
+// Shuffle consumes the seq and returns stream of its elements in random order.
+func Shuffle[V any](seq Seq[V], rng *rand.Rand) Seq[V] {
+	return func(yield func(V) bool) {
+		xs := seq.Slice()
+		zun.Shuffle(xs, rng)
+		FromMany(xs...)(yield)
+	}
+}
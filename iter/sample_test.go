@@ -0,0 +1,72 @@
+package iter_test
+
+import (
+	"math/rand/v2"
+	"slices"
+	"testing"
+
+	"github.com/rprtr258/assert"
+
+	"github.com/rprtr258/fun/iter"
+)
+
+func newRand() *rand.Rand {
+	return rand.New(rand.NewPCG(1, 2))
+}
+
+func TestReservoirSample(t *testing.T) {
+	t.Parallel()
+
+	sample := iter.ReservoirSample(nats.Take(1000), 10, newRand())
+	assert.Equal(t, 10, len(sample))
+	for _, x := range sample {
+		assert.Assert(t, 0 <= x && x < 1000)
+	}
+	assert.Equal(t, sample, iter.ReservoirSample(nats.Take(1000), 10, newRand()))
+
+	assert.Equal(t, []int{0, 1, 2}, iter.ReservoirSample(nats.Take(3), 10, newRand()))
+}
+
+func TestBernoulliSample(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, 10, iter.BernoulliSample(nats10, 1, newRand()).Count())
+	assert.Equal(t, 0, iter.BernoulliSample(nats10, 0, newRand()).Count())
+
+	cnt := iter.BernoulliSample(nats.Take(10000), 0.5, newRand()).Count()
+	assert.Assert(t, 4500 < cnt && cnt < 5500)
+}
+
+func TestWeightedReservoirSample(t *testing.T) {
+	t.Parallel()
+
+	weight := func(x int) float64 { return float64(x % 2) }
+	sample := iter.WeightedReservoirSample(nats.Take(100), 5, weight, newRand())
+	assert.Equal(t, 5, len(sample))
+	for _, x := range sample {
+		assert.Equal(t, 1, x%2)
+	}
+
+	assert.Equal(t, 0, len(iter.WeightedReservoirSample(nats10, 0, weight, newRand())))
+}
+
+func TestShuffle(t *testing.T) {
+	t.Parallel()
+
+	got := iter.Shuffle(nats10, newRand()).Slice()
+	assert.Equal(t, got, iter.Shuffle(nats10, newRand()).Slice())
+	slices.Sort(got)
+	assert.Equal(t, nats10.Slice(), got)
+}
+
+func TestShuffleSlice(t *testing.T) {
+	t.Parallel()
+
+	xs := []int{1, 2, 3, 4, 5}
+	got := iter.ShuffleSlice(xs, newRand()).Slice()
+	assert.Equal(t, []int{1, 2, 3, 4, 5}, xs)
+	slices.Sort(got)
+	assert.Equal(t, xs, got)
+
+	assert.Equal(t, 2, iter.ShuffleSlice(xs, newRand()).Take(2).Count())
+}
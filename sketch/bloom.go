@@ -0,0 +1,125 @@
+package sketch
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+
+	"github.com/rprtr258/fun/iter"
+)
+
+// Bloom is a set membership filter. It never reports added element as absent,
+// but might report absent element as present with bounded probability.
+type Bloom[T comparable] struct {
+	k    int
+	bits []uint64
+}
+
+// maxHashes bounds number of hash functions of deserialized filter,
+// NewBloom never uses more than log2(1/fpRate) <= 1075 of them.
+const maxHashes = 2048
+
+// NewBloom returns empty filter for n elements with false positive rate not exceeding fpRate.
+func NewBloom[T comparable](n uint64, fpRate float64) *Bloom[T] {
+	if fpRate <= 0 || fpRate >= 1 {
+		panic(fmt.Sprintf("False positive rate must be in (0, 1), but %v given", fpRate))
+	}
+
+	n = max(n, 1)
+	m := math.Ceil(-float64(n) * math.Log(fpRate) / (math.Ln2 * math.Ln2))
+	k := max(int(math.Round(m/float64(n)*math.Ln2)), 1)
+	return &Bloom[T]{
+		k:    k,
+		bits: make([]uint64, (int(m)+63)/64),
+	}
+}
+
+func (b *Bloom[T]) bit(h uint64, i int) (int, uint64) {
+	j := hashes(h, i) % uint64(64*len(b.bits))
+	return int(j / 64), 1 << (j % 64)
+}
+
+// Add adds element to the filter.
+func (b *Bloom[T]) Add(t T) {
+	h := hash(t)
+	for i := range b.k {
+		word, mask := b.bit(h, i)
+		b.bits[word] |= mask
+	}
+}
+
+// Contains reports whether element might have been added to the filter.
+func (b *Bloom[T]) Contains(t T) bool {
+	h := hash(t)
+	for i := range b.k {
+		word, mask := b.bit(h, i)
+		if b.bits[word]&mask == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Merge adds all elements of other filter to this one.
+// Filters must have been created with the same parameters.
+func (b *Bloom[T]) Merge(other *Bloom[T]) error {
+	if b.k != other.k || len(b.bits) != len(other.bits) {
+		return ErrIncompatible
+	}
+
+	for i, x := range other.bits {
+		b.bits[i] |= x
+	}
+	return nil
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler.
+func (b *Bloom[T]) MarshalBinary() ([]byte, error) {
+	res := make([]byte, 0, headerSize+8*(1+len(b.bits)))
+	res = appendHeader(res, kindBloom)
+	res = binary.LittleEndian.AppendUint64(res, uint64(b.k))
+	for _, x := range b.bits {
+		res = binary.LittleEndian.AppendUint64(res, x)
+	}
+	return res, nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler.
+func (b *Bloom[T]) UnmarshalBinary(data []byte) error {
+	data, err := readHeader(data, kindBloom)
+	if err != nil {
+		return err
+	}
+
+	var k uint64
+	if k, data, err = readUint64(data); err != nil {
+		return err
+	}
+	if k == 0 || k > maxHashes || len(data) == 0 || len(data)%8 != 0 {
+		return ErrInvalidData
+	}
+
+	b.k = int(k)
+	b.bits = make([]uint64, len(data)/8)
+	for i := range b.bits {
+		b.bits[i], data, _ = readUint64(data)
+	}
+	return nil
+}
+
+// UniqueApprox makes stream of unique elements using memory bounded by Bloom filter
+// sized for n elements. Unlike iter.Unique, it might drop unseen element
+// with probability not exceeding fpRate, but never yields duplicates.
+func UniqueApprox[T comparable](seq iter.Seq[T], n uint64, fpRate float64) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		seen := NewBloom[T](n, fpRate)
+		seq(func(t T) bool {
+			if seen.Contains(t) {
+				return true
+			}
+
+			seen.Add(t)
+			return yield(t)
+		})
+	}
+}
@@ -0,0 +1,129 @@
+package sketch
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+
+	"github.com/rprtr258/fun/iter"
+)
+
+// CountMin estimates elements frequencies. Estimate is never less than the true count,
+// and with probability 1-delta overestimates it by at most epsilon times total count.
+type CountMin[T comparable] struct {
+	width, depth int
+	total        uint64
+	counters     []uint64 // depth rows of width counters
+}
+
+// NewCountMin returns empty sketch with given error bounds.
+func NewCountMin[T comparable](epsilon, delta float64) *CountMin[T] {
+	if epsilon <= 0 || epsilon >= 1 {
+		panic(fmt.Sprintf("Epsilon must be in (0, 1), but %v given", epsilon))
+	}
+	if delta <= 0 || delta >= 1 {
+		panic(fmt.Sprintf("Delta must be in (0, 1), but %v given", delta))
+	}
+
+	width := int(math.Ceil(math.E / epsilon))
+	depth := int(math.Ceil(math.Log(1 / delta)))
+	return &CountMin[T]{
+		width:    width,
+		depth:    depth,
+		counters: make([]uint64, width*depth),
+	}
+}
+
+// AddN adds element to the sketch n times.
+func (c *CountMin[T]) AddN(t T, n uint64) {
+	h := hash(t)
+	for i := range c.depth {
+		c.counters[i*c.width+int(hashes(h, i)%uint64(c.width))] += n
+	}
+	c.total += n
+}
+
+// Add adds element to the sketch.
+func (c *CountMin[T]) Add(t T) {
+	c.AddN(t, 1)
+}
+
+// Estimate returns estimated number of times element was added.
+func (c *CountMin[T]) Estimate(t T) uint64 {
+	h := hash(t)
+	res := uint64(math.MaxUint64)
+	for i := range c.depth {
+		res = min(res, c.counters[i*c.width+int(hashes(h, i)%uint64(c.width))])
+	}
+	return res
+}
+
+// Total returns number of elements added.
+func (c *CountMin[T]) Total() uint64 {
+	return c.total
+}
+
+// Merge adds all elements of other sketch to this one.
+// Sketches must have been created with the same error bounds.
+func (c *CountMin[T]) Merge(other *CountMin[T]) error {
+	if c.width != other.width || c.depth != other.depth {
+		return ErrIncompatible
+	}
+
+	for i, x := range other.counters {
+		c.counters[i] += x
+	}
+	c.total += other.total
+	return nil
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler.
+func (c *CountMin[T]) MarshalBinary() ([]byte, error) {
+	b := make([]byte, 0, headerSize+8*(3+len(c.counters)))
+	b = appendHeader(b, kindCountMin)
+	b = binary.LittleEndian.AppendUint64(b, uint64(c.width))
+	b = binary.LittleEndian.AppendUint64(b, uint64(c.depth))
+	b = binary.LittleEndian.AppendUint64(b, c.total)
+	for _, x := range c.counters {
+		b = binary.LittleEndian.AppendUint64(b, x)
+	}
+	return b, nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler.
+func (c *CountMin[T]) UnmarshalBinary(data []byte) error {
+	data, err := readHeader(data, kindCountMin)
+	if err != nil {
+		return err
+	}
+
+	var width, depth, total uint64
+	if width, data, err = readUint64(data); err != nil {
+		return err
+	}
+	if depth, data, err = readUint64(data); err != nil {
+		return err
+	}
+	if total, data, err = readUint64(data); err != nil {
+		return err
+	}
+	// compare by division, so that corrupted width and depth can't overflow
+	n := uint64(len(data)) / 8
+	if width == 0 || depth == 0 || uint64(len(data))%8 != 0 || n%width != 0 || n/width != depth {
+		return ErrInvalidData
+	}
+
+	c.width, c.depth, c.total = int(width), int(depth), total
+	c.counters = make([]uint64, width*depth)
+	for i := range c.counters {
+		c.counters[i], data, _ = readUint64(data)
+	}
+	return nil
+}
+
+// FrequencyEstimate consumes the seq and returns sketch estimating its elements frequencies.
+func FrequencyEstimate[T comparable](seq iter.Seq[T], epsilon, delta float64) *CountMin[T] {
+	c := NewCountMin[T](epsilon, delta)
+	seq.ForEach(c.Add)
+	return c
+}
@@ -0,0 +1,113 @@
+// Package sketch provides probabilistic data structures to summarize huge streams
+// in bounded memory: cardinality, frequency, heavy hitters and membership.
+// All sketches can be merged to combine summaries of several shards
+// and serialized to move them between processes.
+package sketch
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+)
+
+var (
+	// ErrIncompatible is returned when merging sketches built with different parameters.
+	ErrIncompatible = errors.New("sketch: incompatible parameters")
+	// ErrInvalidData is returned when unmarshaling malformed binary data.
+	ErrInvalidData = errors.New("sketch: invalid data")
+)
+
+// splitmix64 is a finalizer spreading input bits over the whole output.
+func splitmix64(x uint64) uint64 {
+	x += 0x9e3779b97f4a7c15
+	x = (x ^ (x >> 30)) * 0xbf58476d1ce4e5b9
+	x = (x ^ (x >> 27)) * 0x94d049bb133111eb
+	return x ^ (x >> 31)
+}
+
+func fnv1a(b []byte) uint64 {
+	h := uint64(14695981039346656037)
+	for _, c := range b {
+		h ^= uint64(c)
+		h *= 1099511628211
+	}
+	return h
+}
+
+// hash is deterministic across processes, so sketches built on different machines can be merged.
+func hash[T comparable](t T) uint64 {
+	switch v := any(t).(type) {
+	case string:
+		return splitmix64(fnv1a([]byte(v)))
+	case int:
+		return splitmix64(uint64(v))
+	case int8:
+		return splitmix64(uint64(v))
+	case int16:
+		return splitmix64(uint64(v))
+	case int32:
+		return splitmix64(uint64(v))
+	case int64:
+		return splitmix64(uint64(v))
+	case uint:
+		return splitmix64(uint64(v))
+	case uint8:
+		return splitmix64(uint64(v))
+	case uint16:
+		return splitmix64(uint64(v))
+	case uint32:
+		return splitmix64(uint64(v))
+	case uint64:
+		return splitmix64(v)
+	case uintptr:
+		return splitmix64(uint64(v))
+	case float32:
+		return splitmix64(uint64(math.Float32bits(v)))
+	case float64:
+		return splitmix64(math.Float64bits(v))
+	case bool:
+		if v {
+			return splitmix64(1)
+		}
+		return splitmix64(0)
+	default:
+		return splitmix64(fnv1a(fmt.Appendf(nil, "%#v", v)))
+	}
+}
+
+// hashes returns i-th of hash functions family, built from two base hashes
+// as described by Kirsch and Mitzenmacher.
+func hashes(h uint64, i int) uint64 {
+	return h + uint64(i)*(splitmix64(h)|1)
+}
+
+// header is prefix of every serialized sketch: kind tag and format version.
+const headerSize = 2
+
+const version = 1
+
+const (
+	kindHyperLogLog byte = iota + 1
+	kindCountMin
+	kindSpaceSaving
+	kindBloom
+)
+
+func appendHeader(b []byte, kind byte) []byte {
+	return append(b, kind, version)
+}
+
+func readHeader(b []byte, kind byte) ([]byte, error) {
+	if len(b) < headerSize || b[0] != kind || b[1] != version {
+		return nil, ErrInvalidData
+	}
+	return b[headerSize:], nil
+}
+
+func readUint64(b []byte) (uint64, []byte, error) {
+	if len(b) < 8 {
+		return 0, nil, ErrInvalidData
+	}
+	return binary.LittleEndian.Uint64(b), b[8:], nil
+}
@@ -0,0 +1,122 @@
+package sketch
+
+import (
+	"fmt"
+	"math"
+	"math/bits"
+
+	"github.com/rprtr258/fun/iter"
+)
+
+const (
+	minPrecision = 4
+	maxPrecision = 18
+)
+
+// HyperLogLog estimates number of distinct elements using 2^p one-byte registers.
+type HyperLogLog[T comparable] struct {
+	p         uint8
+	registers []uint8
+}
+
+// NewHyperLogLog returns empty sketch with standard relative error close to relErr.
+// Precision is clamped to [4, 18], so relErr below 0.26% gives same precision as 0.26%.
+func NewHyperLogLog[T comparable](relErr float64) *HyperLogLog[T] {
+	if relErr <= 0 || relErr >= 1 {
+		panic(fmt.Sprintf("Relative error must be in (0, 1), but %v given", relErr))
+	}
+
+	// standard error is 1.04/sqrt(m)
+	p := uint8(min(max(math.Ceil(math.Log2(math.Pow(1.04/relErr, 2))), minPrecision), maxPrecision))
+	return &HyperLogLog[T]{
+		p:         p,
+		registers: make([]uint8, 1<<p),
+	}
+}
+
+// Add adds element to the sketch.
+func (h *HyperLogLog[T]) Add(t T) {
+	x := hash(t)
+	idx := x >> (64 - h.p)
+	rho := uint8(min(bits.LeadingZeros64(x<<h.p), 64-int(h.p)) + 1)
+	h.registers[idx] = max(h.registers[idx], rho)
+}
+
+// Count returns estimated number of distinct elements added.
+func (h *HyperLogLog[T]) Count() uint64 {
+	m := float64(len(h.registers))
+	sum, zeros := 0.0, 0
+	for _, r := range h.registers {
+		sum += math.Ldexp(1, -int(r))
+		if r == 0 {
+			zeros++
+		}
+	}
+
+	var alpha float64
+	switch len(h.registers) {
+	case 16:
+		alpha = 0.673
+	case 32:
+		alpha = 0.697
+	case 64:
+		alpha = 0.709
+	default:
+		alpha = 0.7213 / (1 + 1.079/m)
+	}
+
+	estimate := alpha * m * m / sum
+	if estimate <= 2.5*m && zeros != 0 {
+		// linear counting is more precise for small cardinalities
+		estimate = m * math.Log(m/float64(zeros))
+	}
+	return uint64(math.Round(estimate))
+}
+
+// Merge adds all elements of other sketch to this one.
+// Sketches must have been created with the same relative error.
+func (h *HyperLogLog[T]) Merge(other *HyperLogLog[T]) error {
+	if h.p != other.p {
+		return ErrIncompatible
+	}
+
+	for i, r := range other.registers {
+		h.registers[i] = max(h.registers[i], r)
+	}
+	return nil
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler.
+func (h *HyperLogLog[T]) MarshalBinary() ([]byte, error) {
+	b := make([]byte, 0, headerSize+1+len(h.registers))
+	b = appendHeader(b, kindHyperLogLog)
+	b = append(b, h.p)
+	return append(b, h.registers...), nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler.
+func (h *HyperLogLog[T]) UnmarshalBinary(data []byte) error {
+	data, err := readHeader(data, kindHyperLogLog)
+	if err != nil {
+		return err
+	}
+
+	if len(data) < 1 {
+		return ErrInvalidData
+	}
+	p := data[0]
+	if p < minPrecision || p > maxPrecision || len(data[1:]) != 1<<p {
+		return ErrInvalidData
+	}
+
+	h.p = p
+	h.registers = append([]uint8(nil), data[1:]...)
+	return nil
+}
+
+// Distinct consumes the seq and returns sketch estimating number of its distinct elements.
+func Distinct[T comparable](seq iter.Seq[T], relErr float64) *HyperLogLog[T] {
+	h := NewHyperLogLog[T](relErr)
+	seq.ForEach(h.Add)
+	return h
+}
@@ -0,0 +1,203 @@
+package sketch
+
+import (
+	"encoding"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/rprtr258/assert"
+
+	"github.com/rprtr258/fun/iter"
+)
+
+func roundtrip[S interface {
+	encoding.BinaryMarshaler
+	encoding.BinaryUnmarshaler
+}](t *testing.T, from, to S) {
+	t.Helper()
+	b, err := from.MarshalBinary()
+	assert.NoError(t, err)
+	assert.NoError(t, to.UnmarshalBinary(b))
+}
+
+func TestDistinct(t *testing.T) {
+	t.Parallel()
+
+	const n = 100_000
+	h := Distinct(iter.FromInt(n).Repeat().Take(3*n), 0.01)
+	assert.True(t, math.Abs(float64(h.Count())-n)/n < 0.03)
+
+	small := Distinct(iter.FromMany("a", "b", "a", "c"), 0.01)
+	assert.Equal(t, uint64(3), small.Count())
+}
+
+func TestHyperLogLogMerge(t *testing.T) {
+	t.Parallel()
+
+	a := Distinct(iter.FromRange(0, 50_000, 1), 0.01)
+	b := Distinct(iter.FromRange(25_000, 75_000, 1), 0.01)
+	assert.NoError(t, a.Merge(b))
+	assert.True(t, math.Abs(float64(a.Count())-75_000)/75_000 < 0.03)
+
+	assert.Equal(t, ErrIncompatible, a.Merge(NewHyperLogLog[int](0.1)))
+
+	var c HyperLogLog[int]
+	roundtrip(t, a, &c)
+	assert.Equal(t, a.Count(), c.Count())
+	assert.Equal(t, ErrInvalidData, c.UnmarshalBinary([]byte{kindCountMin, version}))
+}
+
+func TestFrequencyEstimate(t *testing.T) {
+	t.Parallel()
+
+	words := iter.Concat(
+		iter.FromMany("a").Repeat().Take(1000),
+		iter.Map(iter.FromInt(10_000), func(i int) string { return fmt.Sprint(i) }),
+	)
+	c := FrequencyEstimate(words, 0.001, 0.01)
+	assert.Equal(t, uint64(11_000), c.Total())
+	assert.True(t, c.Estimate("a") >= 1000 && c.Estimate("a") <= 1000+11)
+	assert.True(t, c.Estimate("b") <= 11)
+
+	other := NewCountMin[string](0.001, 0.01)
+	other.AddN("a", 5)
+	assert.NoError(t, c.Merge(other))
+	assert.True(t, c.Estimate("a") >= 1005)
+	assert.Equal(t, ErrIncompatible, c.Merge(NewCountMin[string](0.1, 0.01)))
+
+	var d CountMin[string]
+	roundtrip(t, c, &d)
+	assert.Equal(t, c.Estimate("a"), d.Estimate("a"))
+	assert.Equal(t, c.Total(), d.Total())
+}
+
+func TestHeavyHitters(t *testing.T) {
+	t.Parallel()
+
+	seq := iter.Interleave(
+		iter.FromMany(1).Repeat().Take(500),
+		iter.FromMany(2).Repeat().Take(300),
+		iter.FromRange(100, 1000, 1),
+	)
+	s := HeavyHitters(seq, 10)
+	top := s.Top()
+	assert.Equal(t, 10, len(top))
+	assert.Equal(t, 1, top[0].Item)
+	assert.Equal(t, 2, top[1].Item)
+	for _, c := range top[:2] {
+		assert.True(t, c.Count-c.Error <= map[int]uint64{1: 500, 2: 300}[c.Item])
+		assert.True(t, c.Count >= map[int]uint64{1: 500, 2: 300}[c.Item])
+	}
+
+	var d SpaceSaving[int]
+	roundtrip(t, s, &d)
+	assert.Equal(t, top, d.Top())
+	assert.Equal(t, s.Total(), d.Total())
+}
+
+func TestSpaceSavingMerge(t *testing.T) {
+	t.Parallel()
+
+	a := HeavyHitters(iter.FromMany("x", "x", "x", "y"), 2)
+	b := HeavyHitters(iter.FromMany("x", "z", "z"), 2)
+	assert.NoError(t, a.Merge(b))
+	top := a.Top()
+	assert.Equal(t, Counter[string]{"x", 4, 0}, top[0])
+	assert.Equal(t, uint64(7), a.Total())
+	assert.Equal(t, ErrIncompatible, a.Merge(NewSpaceSaving[string](3)))
+}
+
+func TestBloom(t *testing.T) {
+	t.Parallel()
+
+	b := NewBloom[int](1000, 0.01)
+	iter.FromInt(1000).ForEach(b.Add)
+	for i := range 1000 {
+		assert.True(t, b.Contains(i))
+	}
+	falsePositives := iter.FromRange(1000, 11000, 1).Filter(b.Contains).Count()
+	assert.True(t, falsePositives < 200)
+
+	other := NewBloom[int](1000, 0.01)
+	other.Add(-1)
+	assert.NoError(t, b.Merge(other))
+	assert.True(t, b.Contains(-1))
+	assert.Equal(t, ErrIncompatible, b.Merge(NewBloom[int](10, 0.01)))
+
+	var c Bloom[int]
+	roundtrip(t, b, &c)
+	assert.True(t, c.Contains(-1) && c.Contains(999))
+}
+
+func TestUniqueApprox(t *testing.T) {
+	t.Parallel()
+
+	got := UniqueApprox(iter.FromMany(1, 2, 1, 3, 2, 1), 100, 0.001).Slice()
+	assert.Equal(t, []int{1, 2, 3}, got)
+}
+
+func encoded(kind byte, words ...uint64) []byte {
+	b := []byte{kind, version}
+	for _, w := range words {
+		b = binary.LittleEndian.AppendUint64(b, w)
+	}
+	return b
+}
+
+func TestUnmarshalCorrupted(t *testing.T) {
+	t.Parallel()
+
+	for name, data := range map[string][]byte{
+		"truncated":       encoded(kindCountMin, 2),
+		"zero width":      encoded(kindCountMin, 0, 1, 0),
+		"size mismatch":   encoded(kindCountMin, 2, 2, 0, 1, 2, 3),
+		"overflowed size": encoded(kindCountMin, 1<<62, 4, 0),
+		"huge dimensions": encoded(kindCountMin, 1<<32, 1<<32, 0, 1),
+	} {
+		t.Run(name, func(t *testing.T) {
+			var c CountMin[int]
+			assert.Equal(t, ErrInvalidData, c.UnmarshalBinary(data))
+		})
+	}
+
+	for name, data := range map[string][]byte{
+		"zero hashes": encoded(kindBloom, 0, 1),
+		"many hashes": encoded(kindBloom, 1<<40, 1),
+		"no bits":     encoded(kindBloom, 3),
+	} {
+		t.Run(name, func(t *testing.T) {
+			var b Bloom[int]
+			assert.Equal(t, ErrInvalidData, b.UnmarshalBinary(data))
+		})
+	}
+}
+
+func FuzzCountMinUnmarshal(f *testing.F) {
+	c := FrequencyEstimate(iter.FromMany(1, 2, 2), 0.1, 0.1)
+	b, _ := c.MarshalBinary()
+	f.Add(b)
+	f.Add(encoded(kindCountMin, 1<<62, 4, 0))
+	f.Fuzz(func(t *testing.T, data []byte) {
+		var c CountMin[int]
+		if c.UnmarshalBinary(data) == nil {
+			c.Add(1)
+			_ = c.Estimate(1)
+		}
+	})
+}
+
+func FuzzBloomUnmarshal(f *testing.F) {
+	bloom := NewBloom[int](10, 0.1)
+	b, _ := bloom.MarshalBinary()
+	f.Add(b)
+	f.Add(encoded(kindBloom, 1<<40, 1))
+	f.Fuzz(func(t *testing.T, data []byte) {
+		var b Bloom[int]
+		if b.UnmarshalBinary(data) == nil {
+			b.Add(1)
+			_ = b.Contains(1)
+		}
+	})
+}
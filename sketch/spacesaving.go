@@ -0,0 +1,212 @@
+package sketch
+
+import (
+	"bytes"
+	"cmp"
+	"container/heap"
+	"encoding/gob"
+	"fmt"
+	"slices"
+
+	"github.com/rprtr258/fun/iter"
+)
+
+// Counter is an element tracked by SpaceSaving along with its estimated count.
+// True count is in [Count-Error, Count].
+type Counter[T any] struct {
+	Item  T
+	Count uint64
+	Error uint64
+}
+
+// counters is min-heap of counters by count, with indices of items.
+type counters[T comparable] struct {
+	heap  []Counter[T]
+	index map[T]int
+}
+
+func (h *counters[T]) Len() int           { return len(h.heap) }
+func (h *counters[T]) Less(i, j int) bool { return h.heap[i].Count < h.heap[j].Count }
+func (h *counters[T]) Swap(i, j int) {
+	h.heap[i], h.heap[j] = h.heap[j], h.heap[i]
+	h.index[h.heap[i].Item] = i
+	h.index[h.heap[j].Item] = j
+}
+
+func (h *counters[T]) Push(x any) {
+	c := x.(Counter[T])
+	h.index[c.Item] = len(h.heap)
+	h.heap = append(h.heap, c)
+}
+
+func (h *counters[T]) Pop() any {
+	c := h.heap[len(h.heap)-1]
+	h.heap = h.heap[:len(h.heap)-1]
+	delete(h.index, c.Item)
+	return c
+}
+
+// SpaceSaving tracks k most frequent elements using k counters.
+// Any element occurring more than total/k times is guaranteed to be tracked.
+type SpaceSaving[T comparable] struct {
+	k        int
+	total    uint64
+	counters counters[T]
+}
+
+// NewSpaceSaving returns empty sketch tracking k most frequent elements.
+func NewSpaceSaving[T comparable](k int) *SpaceSaving[T] {
+	if k <= 0 {
+		panic(fmt.Sprintf("Number of counters must be positive, but %d given", k))
+	}
+
+	return &SpaceSaving[T]{
+		k: k,
+		counters: counters[T]{
+			heap:  make([]Counter[T], 0, k),
+			index: make(map[T]int, k),
+		},
+	}
+}
+
+// AddN adds element to the sketch n times.
+func (s *SpaceSaving[T]) AddN(t T, n uint64) {
+	s.total += n
+	cs := &s.counters
+	if i, ok := cs.index[t]; ok {
+		cs.heap[i].Count += n
+		heap.Fix(cs, i)
+		return
+	}
+
+	if cs.Len() < s.k {
+		heap.Push(cs, Counter[T]{t, n, 0})
+		return
+	}
+
+	// replace least frequent element, inheriting its count as error
+	minCount := cs.heap[0].Count
+	delete(cs.index, cs.heap[0].Item)
+	cs.heap[0] = Counter[T]{t, minCount + n, minCount}
+	cs.index[t] = 0
+	heap.Fix(cs, 0)
+}
+
+// Add adds element to the sketch.
+func (s *SpaceSaving[T]) Add(t T) {
+	s.AddN(t, 1)
+}
+
+// Total returns number of elements added.
+func (s *SpaceSaving[T]) Total() uint64 {
+	return s.total
+}
+
+// Top returns tracked elements sorted by estimated count in descending order.
+func (s *SpaceSaving[T]) Top() []Counter[T] {
+	res := slices.Clone(s.counters.heap)
+	slices.SortStableFunc(res, func(a, b Counter[T]) int {
+		return cmp.Compare(b.Count, a.Count)
+	})
+	return res
+}
+
+func (s *SpaceSaving[T]) minCount() uint64 {
+	if s.counters.Len() < s.k {
+		return 0
+	}
+	return s.counters.heap[0].Count
+}
+
+// Merge adds all elements of other sketch to this one.
+// Sketches must track the same number of elements.
+func (s *SpaceSaving[T]) Merge(other *SpaceSaving[T]) error {
+	if s.k != other.k {
+		return ErrIncompatible
+	}
+
+	// element not tracked by sketch might have occurred there up to its min count times
+	minS, minOther := s.minCount(), other.minCount()
+	merged := make(map[T]Counter[T], s.counters.Len()+other.counters.Len())
+	for _, c := range s.counters.heap {
+		merged[c.Item] = Counter[T]{c.Item, c.Count + minOther, c.Error + minOther}
+	}
+	for _, c := range other.counters.heap {
+		if m, ok := merged[c.Item]; ok {
+			merged[c.Item] = Counter[T]{c.Item, m.Count - minOther + c.Count, m.Error - minOther + c.Error}
+		} else {
+			merged[c.Item] = Counter[T]{c.Item, c.Count + minS, c.Error + minS}
+		}
+	}
+
+	all := make([]Counter[T], 0, len(merged))
+	for _, c := range merged {
+		all = append(all, c)
+	}
+	slices.SortFunc(all, func(a, b Counter[T]) int {
+		return cmp.Compare(b.Count, a.Count)
+	})
+	s.setCounters(all[:min(len(all), s.k)])
+	s.total += other.total
+	return nil
+}
+
+func (s *SpaceSaving[T]) setCounters(cs []Counter[T]) {
+	s.counters = counters[T]{
+		heap:  slices.Clone(cs),
+		index: make(map[T]int, s.k),
+	}
+	for i, c := range s.counters.heap {
+		s.counters.index[c.Item] = i
+	}
+	heap.Init(&s.counters)
+}
+
+// spaceSavingData is serialized form of SpaceSaving.
+type spaceSavingData[T any] struct {
+	K        int
+	Total    uint64
+	Counters []Counter[T]
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler.
+// Elements are encoded using encoding/gob, so T must be gob-encodable.
+func (s *SpaceSaving[T]) MarshalBinary() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.Write(appendHeader(nil, kindSpaceSaving))
+	if err := gob.NewEncoder(&buf).Encode(spaceSavingData[T]{
+		K:        s.k,
+		Total:    s.total,
+		Counters: s.counters.heap,
+	}); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler.
+func (s *SpaceSaving[T]) UnmarshalBinary(data []byte) error {
+	data, err := readHeader(data, kindSpaceSaving)
+	if err != nil {
+		return err
+	}
+
+	var d spaceSavingData[T]
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&d); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidData, err)
+	}
+	if d.K <= 0 || len(d.Counters) > d.K {
+		return ErrInvalidData
+	}
+
+	s.k, s.total = d.K, d.Total
+	s.setCounters(d.Counters)
+	return nil
+}
+
+// HeavyHitters consumes the seq and returns sketch tracking its k most frequent elements.
+func HeavyHitters[T comparable](seq iter.Seq[T], k int) *SpaceSaving[T] {
+	s := NewSpaceSaving[T](k)
+	seq.ForEach(s.Add)
+	return s
+}
@@ -0,0 +1,284 @@
+package text
+
+import (
+	"encoding"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+
+	"github.com/rprtr258/fun/iter"
+)
+
+// CSVOptions configures typed CSV reading and writing.
+type CSVOptions struct {
+	// Comma is field delimiter, ',' if zero. Use '\t' for TSV.
+	Comma rune
+	// Comment, if not zero, is a character starting comment lines, which are ignored on reading.
+	Comment rune
+	// Parsers are custom parsers for columns, by column name.
+	// Parsed value must be assignable to the field.
+	Parsers map[string]func(string) (any, error)
+	// Formatters are custom formatters for columns, by column name.
+	Formatters map[string]func(any) (string, error)
+}
+
+// CSVError is an error in record field conversion.
+type CSVError struct {
+	Line, Column int // 1-based position of the field start
+	Field        string
+	Err          error
+}
+
+func (e *CSVError) Error() string {
+	return fmt.Sprintf("record on line %d, column %d, field %q: %v", e.Line, e.Column, e.Field, e.Err)
+}
+
+func (e *CSVError) Unwrap() error {
+	return e.Err
+}
+
+type csvField struct {
+	name  string
+	index []int
+}
+
+// csvFields lists fields of struct type T along with their column names.
+// Column name is taken from `csv` tag or field name, fields tagged `csv:"-"` are skipped.
+func csvFields[T any]() []csvField {
+	typ := reflect.TypeFor[T]()
+	if typ.Kind() != reflect.Struct {
+		panic(fmt.Sprintf("CSV record type must be a struct, but %v given", typ))
+	}
+
+	var res []csvField
+	for _, f := range reflect.VisibleFields(typ) {
+		if !f.IsExported() || f.Anonymous || !csvSettable(typ, f.Index) {
+			continue
+		}
+
+		name := f.Name
+		if tag, ok := f.Tag.Lookup("csv"); ok {
+			if tag == "-" {
+				continue
+			}
+			if tag != "" {
+				name = tag
+			}
+		}
+		res = append(res, csvField{name, f.Index})
+	}
+	return res
+}
+
+// csvSettable reports whether field by index can be set, nil pointers promoting
+// it through unexported embedded fields cannot be allocated.
+func csvSettable(typ reflect.Type, index []int) bool {
+	for _, x := range index[:len(index)-1] {
+		f := typ.Field(x)
+		typ = f.Type
+		if typ.Kind() == reflect.Pointer {
+			if !f.IsExported() {
+				return false
+			}
+			typ = typ.Elem()
+		}
+	}
+	return true
+}
+
+// csvFieldAlloc returns field by index, allocating nil embedded pointers on the way.
+func csvFieldAlloc(v reflect.Value, index []int) reflect.Value {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v
+}
+
+func parseCSVValue(s string, v reflect.Value) error {
+	if u, ok := v.Addr().Interface().(encoding.TextUnmarshaler); ok {
+		return u.UnmarshalText([]byte(s))
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(s)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(s, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		u, err := strconv.ParseUint(s, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetUint(u)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(s, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetFloat(f)
+	default:
+		return fmt.Errorf("unsupported field type %v", v.Type())
+	}
+	return nil
+}
+
+func formatCSVValue(v reflect.Value) (string, error) {
+	if m, ok := v.Interface().(encoding.TextMarshaler); ok {
+		b, err := m.MarshalText()
+		return string(b), err
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		return v.String(), nil
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool()), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(v.Uint(), 10), nil
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'g', -1, v.Type().Bits()), nil
+	default:
+		return "", fmt.Errorf("unsupported field type %v", v.Type())
+	}
+}
+
+// ReadCSV reads CSV records with header into structs of type T.
+// Header columns are mapped to struct fields by `csv` tags or field names,
+// unknown columns are ignored. Error, if any, is yielded as the last element.
+func ReadCSV[T any](r io.Reader, opts CSVOptions) iter.Seq2[T, error] {
+	fields := csvFields[T]()
+	return func(yield func(T, error) bool) {
+		cr := csv.NewReader(r)
+		if opts.Comma != 0 {
+			cr.Comma = opts.Comma
+		}
+		cr.Comment = opts.Comment
+
+		var (
+			columns  []int // field index for each column, -1 if unknown
+			zero     T
+			isHeader = true
+		)
+		for record, err := range iter.FromCSV(cr) {
+			if err != nil {
+				yield(zero, err)
+				return
+			}
+
+			if isHeader {
+				isHeader = false
+				columns = make([]int, len(record))
+				for i, name := range record {
+					columns[i] = -1
+					for j, f := range fields {
+						if f.name == name {
+							columns[i] = j
+						}
+					}
+				}
+				continue
+			}
+
+			var t T
+			v := reflect.ValueOf(&t).Elem()
+			for i, value := range record {
+				if i >= len(columns) || columns[i] == -1 {
+					continue
+				}
+
+				f := fields[columns[i]]
+				fv := csvFieldAlloc(v, f.index)
+				var err error
+				if parse, ok := opts.Parsers[f.name]; ok {
+					var x any
+					if x, err = parse(value); err == nil {
+						xv := reflect.ValueOf(x)
+						if xv.IsValid() && xv.Type().AssignableTo(fv.Type()) {
+							fv.Set(xv)
+						} else {
+							err = fmt.Errorf("parsed value of type %T is not assignable to %v", x, fv.Type())
+						}
+					}
+				} else {
+					err = parseCSVValue(value, fv)
+				}
+				if err != nil {
+					line, column := cr.FieldPos(i)
+					yield(zero, &CSVError{line, column, f.name, err})
+					return
+				}
+			}
+
+			if !yield(t, nil) {
+				return
+			}
+		}
+	}
+}
+
+// WriteCSV writes structs of type T as CSV records with header.
+// Header is made from `csv` tags or field names. Writing stops on first error, which is returned.
+func WriteCSV[T any](w io.Writer, seq iter.Seq[T], opts CSVOptions) error {
+	fields := csvFields[T]()
+
+	cw := csv.NewWriter(w)
+	if opts.Comma != 0 {
+		cw.Comma = opts.Comma
+	}
+
+	record := make([]string, len(fields))
+	for i, f := range fields {
+		record[i] = f.name
+	}
+	if err := cw.Write(record); err != nil {
+		return err
+	}
+
+	for t := range seq {
+		v := reflect.ValueOf(t)
+		for i, f := range fields {
+			fv, err := v.FieldByIndexErr(f.index)
+			if err != nil {
+				// field promoted through nil embedded pointer
+				record[i] = ""
+				continue
+			}
+
+			if format, ok := opts.Formatters[f.name]; ok {
+				record[i], err = format(fv.Interface())
+			} else {
+				record[i], err = formatCSVValue(fv)
+			}
+			if err != nil {
+				return fmt.Errorf("field %q: %w", f.name, err)
+			}
+		}
+
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
@@ -0,0 +1,118 @@
+package text
+
+import (
+	"bytes"
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rprtr258/assert"
+
+	"github.com/rprtr258/fun/iter"
+)
+
+type csvRecord struct {
+	ID      int       `csv:"id"`
+	Name    string    `csv:"name"`
+	Score   float64   `csv:"score"`
+	Created time.Time `csv:"created"`
+	Secret  string    `csv:"-"`
+}
+
+func TestReadCSV(t *testing.T) {
+	t.Parallel()
+
+	input := "name,id,extra,score,created\n" +
+		"Sam,1,x,1.5,2024-01-02T03:04:05Z\n" +
+		"\"Multi\nline\",2,y,2,2024-01-02T03:04:05Z\n"
+	created := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	assert.Equal(t, []csvRecord{
+		{1, "Sam", 1.5, created, ""},
+		{2, "Multi\nline", 2, created, ""},
+	}, collectSeq2(t, ReadCSV[csvRecord](strings.NewReader(input), CSVOptions{})))
+}
+
+func TestReadCSVOptions(t *testing.T) {
+	t.Parallel()
+
+	input := "# comment\nid\tname\n0x10\tSam\n"
+	got := collectSeq2(t, ReadCSV[csvRecord](strings.NewReader(input), CSVOptions{
+		Comma:   '\t',
+		Comment: '#',
+		Parsers: map[string]func(string) (any, error){
+			"id": func(s string) (any, error) {
+				i, err := strconv.ParseInt(s, 0, 64)
+				return int(i), err
+			},
+		},
+	}))
+	assert.Equal(t, []csvRecord{{ID: 16, Name: "Sam"}}, got)
+}
+
+func TestReadCSVError(t *testing.T) {
+	t.Parallel()
+
+	input := "id,name\n1,\"a\nb\"\n2,c\nthree,d\n"
+	var gotErr error
+	cnt := 0
+	for _, err := range ReadCSV[csvRecord](strings.NewReader(input), CSVOptions{}) {
+		if err != nil {
+			gotErr = err
+			continue
+		}
+		cnt++
+	}
+	assert.Equal(t, 2, cnt)
+	var csvErr *CSVError
+	assert.True(t, errors.As(gotErr, &csvErr))
+	assert.Equal(t, 5, csvErr.Line)
+	assert.Equal(t, 1, csvErr.Column)
+	assert.Equal(t, "id", csvErr.Field)
+	assert.True(t, errors.Is(gotErr, strconv.ErrSyntax))
+}
+
+func TestWriteCSV(t *testing.T) {
+	t.Parallel()
+
+	created := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	records := []csvRecord{
+		{1, "Sam", 1.5, created, "secret"},
+		{2, "Multi\nline", 2, created, ""},
+	}
+
+	var buf bytes.Buffer
+	assert.NoError(t, WriteCSV(&buf, iter.FromMany(records...), CSVOptions{Comma: '\t'}))
+	assert.Equal(t, "id\tname\tscore\tcreated\n"+
+		"1\tSam\t1.5\t2024-01-02T03:04:05Z\n"+
+		"2\t\"Multi\nline\"\t2\t2024-01-02T03:04:05Z\n", buf.String())
+
+	records[0].Secret = ""
+	assert.Equal(t, records, collectSeq2(t, ReadCSV[csvRecord](&buf, CSVOptions{Comma: '\t'})))
+}
+
+type CSVEmbedded struct {
+	Name string `csv:"name"`
+}
+
+type csvHidden struct {
+	Note string `csv:"note"`
+}
+
+type csvEmbedding struct {
+	ID int `csv:"id"`
+	*CSVEmbedded
+	*csvHidden
+}
+
+func TestCSVNilEmbedded(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	assert.NoError(t, WriteCSV(&buf, iter.FromMany(csvEmbedding{ID: 1}), CSVOptions{}))
+	assert.Equal(t, "id,name\n1,\n", buf.String())
+
+	got := collectSeq2(t, ReadCSV[csvEmbedding](strings.NewReader("id,name,note\n2,Sam,x\n"), CSVOptions{}))
+	assert.Equal(t, []csvEmbedding{{2, &CSVEmbedded{"Sam"}, nil}}, got)
+}
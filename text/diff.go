@@ -0,0 +1,222 @@
+package text
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+
+	"github.com/rprtr258/fun"
+	"github.com/rprtr258/fun/iter"
+)
+
+// DefaultContext is conventional number of context lines in unified diff.
+const DefaultContext = 3
+
+// DiffOptions configures lines diff.
+type DiffOptions struct {
+	// Patience makes diff use patience algorithm, which aligns unique lines first.
+	// It usually gives more readable diffs for source code, but might be not minimal.
+	Patience bool
+}
+
+// Diff computes edit script transforming lines of a into lines of b.
+// Myers' algorithm is used by default, giving minimal edit script.
+func Diff(a, b iter.Seq[string], opts DiffOptions) []fun.Edit[string] {
+	as, bs := a.Slice(), b.Slice()
+	if opts.Patience {
+		return patienceDiff(as, bs, 0, 0)
+	}
+	return fun.Diff(as, bs)
+}
+
+func shiftEdits(edits []fun.Edit[string], da, db int) []fun.Edit[string] {
+	for i := range edits {
+		edits[i].A += da
+		edits[i].B += db
+	}
+	return edits
+}
+
+// patienceDiff diffs a and b, which are subslices starting at aOff and bOff of whole sequences.
+func patienceDiff(a, b []string, aOff, bOff int) []fun.Edit[string] {
+	var res []fun.Edit[string]
+	equal := func(i, j int) {
+		res = append(res, fun.Edit[string]{Kind: fun.EditEqual, A: aOff + i, B: bOff + j, Value: a[i]})
+	}
+
+	// common prefix and suffix
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		equal(prefix, prefix)
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+	ma, mb := a[prefix:len(a)-suffix], b[prefix:len(b)-suffix]
+
+	anchors := patienceAnchors(ma, mb)
+	if len(anchors) == 0 {
+		res = append(res, shiftEdits(fun.Diff(ma, mb), aOff+prefix, bOff+prefix)...)
+	} else {
+		i, j := 0, 0
+		for _, anchor := range anchors {
+			res = append(res, patienceDiff(ma[i:anchor.K], mb[j:anchor.V], aOff+prefix+i, bOff+prefix+j)...)
+			equal(prefix+anchor.K, prefix+anchor.V)
+			i, j = anchor.K+1, anchor.V+1
+		}
+		res = append(res, patienceDiff(ma[i:], mb[j:], aOff+prefix+i, bOff+prefix+j)...)
+	}
+
+	for k := suffix; k > 0; k-- {
+		equal(len(a)-k, len(b)-k)
+	}
+	return res
+}
+
+// patienceAnchors finds longest increasing sequence of pairs of indices of lines unique in both a and b.
+func patienceAnchors(a, b []string) []fun.Pair[int, int] {
+	type occurrence struct {
+		count, index int
+	}
+	inA := map[string]occurrence{}
+	for i, line := range a {
+		inA[line] = occurrence{inA[line].count + 1, i}
+	}
+	inB := map[string]occurrence{}
+	for j, line := range b {
+		inB[line] = occurrence{inB[line].count + 1, j}
+	}
+
+	var candidates []fun.Pair[int, int]
+	for i, line := range a {
+		if inA[line].count == 1 && inB[line].count == 1 {
+			candidates = append(candidates, fun.Pair[int, int]{K: i, V: inB[line].index})
+		}
+	}
+
+	// patience sorting: piles keep index of top candidate, prev links candidate to pile to the left
+	var piles []int
+	prev := make([]int, len(candidates))
+	for c, cand := range candidates {
+		lo, hi := 0, len(piles)
+		for lo < hi {
+			mid := (lo + hi) / 2
+			if candidates[piles[mid]].V < cand.V {
+				lo = mid + 1
+			} else {
+				hi = mid
+			}
+		}
+		prev[c] = -1
+		if lo > 0 {
+			prev[c] = piles[lo-1]
+		}
+		if lo == len(piles) {
+			piles = append(piles, c)
+		} else {
+			piles[lo] = c
+		}
+	}
+
+	if len(piles) == 0 {
+		return nil
+	}
+	res := make([]fun.Pair[int, int], len(piles))
+	for i, c := len(piles)-1, piles[len(piles)-1]; c != -1; i, c = i-1, prev[c] {
+		res[i] = candidates[c]
+	}
+	return res
+}
+
+// UnifiedOptions configures unified diff rendering.
+type UnifiedOptions struct {
+	// FromFile and ToFile are names in diff header. Header is omitted if both are empty.
+	FromFile, ToFile string
+	// Context is number of unchanged lines shown around changes, DefaultContext if zero.
+	// Negative Context shows no unchanged lines.
+	Context int
+}
+
+// hunkRange formats hunk range in unified diff format. Empty range refers to the line before it.
+func hunkRange(start, count int) string {
+	switch count {
+	case 0:
+		return fmt.Sprintf("%d,0", start)
+	case 1:
+		return fmt.Sprintf("%d", start+1)
+	default:
+		return fmt.Sprintf("%d,%d", start+1, count)
+	}
+}
+
+// WriteUnified renders edit script as unified diff. Nothing is written if there are no changes.
+func WriteUnified(w io.Writer, edits []fun.Edit[string], opts UnifiedOptions) error {
+	ctx := max(opts.Context, 0)
+	if opts.Context == 0 {
+		ctx = DefaultContext
+	}
+	bw := bufio.NewWriter(w)
+
+	headerWritten := opts.FromFile == "" && opts.ToFile == ""
+	for start := 0; start < len(edits); {
+		// find next change
+		for start < len(edits) && edits[start].Kind == fun.EditEqual {
+			start++
+		}
+		if start == len(edits) {
+			break
+		}
+
+		// extend hunk while changes are separated by at most 2*ctx equal lines
+		end := start
+		for i := start; i < len(edits); i++ {
+			if edits[i].Kind != fun.EditEqual {
+				end = i + 1
+			} else if i-end >= 2*ctx {
+				break
+			}
+		}
+		from, to := max(start-ctx, 0), min(end+ctx, len(edits))
+
+		if !headerWritten {
+			headerWritten = true
+			if _, err := fmt.Fprintf(bw, "--- %s\n+++ %s\n", opts.FromFile, opts.ToFile); err != nil {
+				return err
+			}
+		}
+
+		countA, countB := 0, 0
+		for _, e := range edits[from:to] {
+			if e.Kind != fun.EditInsert {
+				countA++
+			}
+			if e.Kind != fun.EditDelete {
+				countB++
+			}
+		}
+		if _, err := fmt.Fprintf(bw, "@@ -%s +%s @@\n",
+			hunkRange(edits[from].A, countA),
+			hunkRange(edits[from].B, countB),
+		); err != nil {
+			return err
+		}
+
+		for _, e := range edits[from:to] {
+			prefix := " "
+			switch e.Kind {
+			case fun.EditDelete:
+				prefix = "-"
+			case fun.EditInsert:
+				prefix = "+"
+			}
+			if _, err := bw.WriteString(prefix + e.Value + endline); err != nil {
+				return err
+			}
+		}
+
+		start = to
+	}
+	return bw.Flush()
+}
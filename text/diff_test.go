@@ -0,0 +1,113 @@
+package text
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/rprtr258/assert"
+	"github.com/rprtr258/fun"
+
+	"github.com/rprtr258/fun/iter"
+)
+
+var (
+	diffA = iter.FromMany(strings.Split("a b c d e f g h i j k", " ")...)
+	diffB = iter.FromMany(strings.Split("a B c d e f g h i k l", " ")...)
+)
+
+func unified(t *testing.T, edits []fun.Edit[string], opts UnifiedOptions) string {
+	t.Helper()
+	var buf bytes.Buffer
+	assert.NoError(t, WriteUnified(&buf, edits, opts))
+	return buf.String()
+}
+
+func TestDiffUnified(t *testing.T) {
+	t.Parallel()
+
+	edits := Diff(diffA, diffB, DiffOptions{})
+	assert.Equal(t, `--- x.txt
++++ y.txt
+@@ -1,3 +1,3 @@
+ a
+-b
++B
+ c
+@@ -9,3 +9,3 @@
+ i
+-j
+ k
++l
+`, unified(t, edits, UnifiedOptions{FromFile: "x.txt", ToFile: "y.txt", Context: 1}))
+
+	assert.Equal(t, `@@ -2 +2 @@
+-b
++B
+@@ -10 +9,0 @@
+-j
+@@ -11,0 +11 @@
++l
+`, unified(t, edits, UnifiedOptions{Context: -1}))
+
+	assert.Equal(t, `@@ -1,5 +1,5 @@
+ a
+-b
++B
+ c
+ d
+ e
+@@ -7,5 +7,5 @@
+ g
+ h
+ i
+-j
+ k
++l
+`, unified(t, edits, UnifiedOptions{Context: DefaultContext}))
+
+	assert.Equal(t, unified(t, edits, UnifiedOptions{Context: DefaultContext}), unified(t, edits, UnifiedOptions{}))
+	assert.Equal(t, "", unified(t, Diff(diffA, diffA, DiffOptions{}), UnifiedOptions{FromFile: "a", ToFile: "b"}))
+}
+
+func TestDiffPatience(t *testing.T) {
+	t.Parallel()
+
+	a := strings.Split("int f() {\n  return 1;\n}\n\nint g() {\n  return 2;\n}", "\n")
+	b := strings.Split("int g() {\n  return 2;\n}\n\nint f() {\n  return 1;\n}\n\nint h() {\n  return 3;\n}", "\n")
+	for _, opts := range []DiffOptions{{}, {Patience: true}} {
+		edits := Diff(iter.FromMany(a...), iter.FromMany(b...), opts)
+		gotA, gotB := []string{}, []string{}
+		for _, e := range edits {
+			if e.Kind != fun.EditInsert {
+				assert.Equal(t, len(gotA), e.A)
+				gotA = append(gotA, e.Value)
+			}
+			if e.Kind != fun.EditDelete {
+				assert.Equal(t, len(gotB), e.B)
+				gotB = append(gotB, e.Value)
+			}
+		}
+		assert.Equal(t, a, gotA)
+		assert.Equal(t, b, gotB)
+	}
+
+	// patience keeps unique lines aligned, so functions are moved and added as whole blocks
+	assert.Equal(t, `@@ -1,7 +1,11 @@
+-int f() {
+-  return 1;
+-}
+-
+ int g() {
+   return 2;
++}
++
++int f() {
++  return 1;
++}
++
++int h() {
++  return 3;
+ }
+`, unified(t, Diff(iter.FromMany(a...), iter.FromMany(b...), DiffOptions{Patience: true}), UnifiedOptions{}))
+}
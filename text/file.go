@@ -0,0 +1,144 @@
+package text
+
+import (
+	"bufio"
+	"bytes"
+	"compress/bzip2"
+	"compress/gzip"
+	"compress/zlib"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// ErrUnsupportedCompression is returned when creating file with compression that can only be read.
+var ErrUnsupportedCompression = errors.New("unsupported compression")
+
+type compression int
+
+const (
+	compressionNone compression = iota
+	compressionGzip
+	compressionZlib
+	compressionBzip2
+)
+
+func compressionByExt(path string) compression {
+	switch filepath.Ext(path) {
+	case ".gz":
+		return compressionGzip
+	case ".zz", ".zlib":
+		return compressionZlib
+	case ".bz2":
+		return compressionBzip2
+	default:
+		return compressionNone
+	}
+}
+
+func compressionByMagic(magic []byte) compression {
+	switch {
+	case bytes.HasPrefix(magic, []byte{0x1f, 0x8b}):
+		return compressionGzip
+	// "BZh" followed by block size from '1' to '9'
+	case len(magic) >= 4 && bytes.HasPrefix(magic, []byte("BZh")) && '1' <= magic[3] && magic[3] <= '9':
+		return compressionBzip2
+	// zlib header with default window size and one of standard compression levels,
+	// other valid headers are printable and might be just text
+	case len(magic) >= 2 && magic[0] == 0x78 && bytes.IndexByte([]byte{0x01, 0x9c, 0xda}, magic[1]) != -1:
+		return compressionZlib
+	default:
+		return compressionNone
+	}
+}
+
+// layers is a stack of readers or writers, closed from the top to the bottom.
+type layers []io.Closer
+
+func (ls layers) Close() error {
+	var errs []error
+	for _, l := range ls {
+		if err := l.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+type readCloser struct {
+	io.Reader
+	layers
+}
+
+type writeCloser struct {
+	io.Writer
+	layers
+}
+
+// Open opens file for reading, transparently decompressing it.
+// Compression is detected by magic bytes or, failing that, by file extension:
+// gzip (.gz), zlib (.zz, .zlib) and bzip2 (.bz2) are supported.
+// Closing returned reader closes the file too.
+func Open(path string) (io.ReadCloser, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	br := bufio.NewReader(f)
+	magic, _ := br.Peek(4)
+	c := compressionByMagic(magic)
+	if c == compressionNone {
+		c = compressionByExt(path)
+	}
+
+	var r io.Reader
+	ls := layers{f}
+	switch c {
+	case compressionGzip:
+		gr, err := gzip.NewReader(br)
+		if err != nil {
+			return nil, errors.Join(fmt.Errorf("open %s: %w", path, err), f.Close())
+		}
+		r, ls = gr, layers{gr, f}
+	case compressionZlib:
+		zr, err := zlib.NewReader(br)
+		if err != nil {
+			return nil, errors.Join(fmt.Errorf("open %s: %w", path, err), f.Close())
+		}
+		r, ls = zr, layers{zr, f}
+	case compressionBzip2:
+		r = bzip2.NewReader(br)
+	default:
+		r = br
+	}
+	return readCloser{r, ls}, nil
+}
+
+// Create creates or truncates file for writing, transparently compressing it
+// according to file extension: gzip (.gz) and zlib (.zz, .zlib) are supported.
+// Closing returned writer flushes compressed data and closes the file.
+func Create(path string) (io.WriteCloser, error) {
+	c := compressionByExt(path)
+	if c == compressionBzip2 {
+		return nil, fmt.Errorf("create %s: bzip2: %w", path, ErrUnsupportedCompression)
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+
+	switch c {
+	case compressionGzip:
+		gw := gzip.NewWriter(f)
+		return writeCloser{gw, layers{gw, f}}, nil
+	case compressionZlib:
+		zw := zlib.NewWriter(f)
+		return writeCloser{zw, layers{zw, f}}, nil
+	default:
+		return f, nil
+	}
+}
@@ -2,7 +2,12 @@ package text
 
 import (
 	"bytes"
+	"compress/gzip"
+	"errors"
+	"io"
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/rprtr258/assert"
@@ -126,6 +131,189 @@ func TestWrite(t *testing.T) {
 	t.Parallel()
 
 	buf := bytes.NewBuffer(make([]byte, 0, 1000))
-	WriteByteChunks(buf, s.FromMany([]byte("a"), []byte("bc")))
+	assert.NoError(t, WriteByteChunks(buf, s.FromMany([]byte("a"), []byte("bc"))))
 	assert.Equal(t, "abc", buf.String())
 }
+
+func TestWriteLines(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	assert.NoError(t, WriteLines(&buf, s.FromMany("a", "b")))
+	assert.Equal(t, "a\nb", buf.String())
+
+	buf.Reset()
+	assert.NoError(t, WriteLinesWith(&buf, s.FromMany("a", "b"), WriteLinesOptions{TrailingNewline: true}))
+	assert.Equal(t, "a\nb\n", buf.String())
+
+	buf.Reset()
+	assert.NoError(t, WriteLinesWith(&buf, s.FromNothing[string](), WriteLinesOptions{TrailingNewline: true}))
+	assert.Equal(t, "", buf.String())
+}
+
+// limitedWriter fails after n bytes are written.
+type limitedWriter struct {
+	n int
+}
+
+var errWrite = errors.New("disk full")
+
+func (w *limitedWriter) Write(b []byte) (int, error) {
+	if len(b) > w.n {
+		n := w.n
+		w.n = 0
+		return n, errWrite
+	}
+	w.n -= len(b)
+	return len(b), nil
+}
+
+func TestWriteLinesError(t *testing.T) {
+	t.Parallel()
+
+	consumed := 0
+	lines := s.Map(s.FromInt(10_000), func(i int) string {
+		consumed++
+		return "line"
+	})
+	err := WriteLines(&limitedWriter{n: 10}, lines)
+	assert.True(t, errors.Is(err, errWrite))
+	assert.True(t, consumed < 10_000)
+
+	err = WriteByteChunks(&limitedWriter{n: 10}, s.FromMany([]byte("0123456789abcdef")))
+	assert.True(t, errors.Is(err, errWrite))
+}
+
+func collectSeq2[T any](t *testing.T, seq s.Seq2[T, error]) []T {
+	t.Helper()
+	res := []T{}
+	for x, err := range seq {
+		assert.NoError(t, err)
+		res = append(res, x)
+	}
+	return res
+}
+
+func TestLines(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, []string{"", "Line 2", "Line 30"}, collectSeq2(t, Lines(bytes.NewReader([]byte(exampleText)))))
+	assert.Equal(t, []string{"a", "", "b", "c"}, collectSeq2(t, Lines(strings.NewReader("a\r\n\r\nb\nc"))))
+	assert.Equal(t, []string{}, collectSeq2(t, Lines(strings.NewReader(""))))
+	assert.Equal(t, []string{"a", "b\n"}, collectSeq2(t, LinesWith(strings.NewReader("a||b\n||"), LinesOptions{Separator: "||"})))
+}
+
+func TestReadLines(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, []string{"", "Line 2", "Line 30"}, ReadLines(bytes.NewReader([]byte(exampleText))).Slice())
+}
+
+func TestLinesMaxLength(t *testing.T) {
+	t.Parallel()
+
+	lines, errs := []string{}, []error{}
+	for line, err := range LinesWith(strings.NewReader("abc\r\nabcd\nabcdefgh\nab"), LinesOptions{MaxLength: 3}) {
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		lines = append(lines, line)
+	}
+	assert.Equal(t, []string{"abc"}, lines)
+	assert.Equal(t, 1, len(errs))
+	assert.True(t, errors.Is(errs[0], ErrLineTooLong))
+	assert.Equal(t, "line 2: line too long", errs[0].Error())
+}
+
+type failingReader struct{}
+
+var errRead = errors.New("read failed")
+
+func (failingReader) Read([]byte) (int, error) {
+	return 0, errRead
+}
+
+func TestLinesError(t *testing.T) {
+	t.Parallel()
+
+	var gotErr error
+	for _, err := range Lines(io.MultiReader(strings.NewReader("a\n"), failingReader{})) {
+		gotErr = err
+	}
+	assert.True(t, errors.Is(gotErr, errRead))
+	assert.Equal(t, "line 2: read failed", gotErr.Error())
+}
+
+func TestOpenCreate(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	for _, name := range []string{"plain.txt", "a.gz", "a.zz", "a.zlib"} {
+		path := filepath.Join(dir, name)
+		w, err := Create(path)
+		assert.NoError(t, err)
+		assert.NoError(t, WriteLines(w, s.FromMany("a", "b")))
+		assert.NoError(t, w.Close())
+
+		r, err := Open(path)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"a", "b"}, ReadLines(r).Slice())
+		assert.NoError(t, r.Close())
+	}
+}
+
+func TestOpenByMagic(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	gw := gzip.NewWriter(&buf)
+	_, err := gw.Write([]byte("compressed\n"))
+	assert.NoError(t, err)
+	assert.NoError(t, gw.Close())
+
+	path := filepath.Join(t.TempDir(), "no-extension")
+	assert.NoError(t, os.WriteFile(path, buf.Bytes(), 0o600))
+
+	r, err := Open(path)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"compressed"}, ReadLines(r).Slice())
+	assert.NoError(t, r.Close())
+}
+
+func TestOpenBzip2(t *testing.T) {
+	t.Parallel()
+
+	// bzip2 compressed "a\nb\n"
+	data := []byte{
+		0x42, 0x5a, 0x68, 0x39, 0x31, 0x41, 0x59, 0x26, 0x53, 0x59, 0x3c, 0x85, 0x41, 0x12,
+		0x00, 0x00, 0x01, 0x41, 0x00, 0x00, 0x10, 0x30, 0x00, 0x20, 0x00, 0x30, 0xcc, 0x0c,
+		0x7a, 0x82, 0x71, 0x77, 0x24, 0x53, 0x85, 0x09, 0x03, 0xc8, 0x54, 0x11, 0x20,
+	}
+	path := filepath.Join(t.TempDir(), "a.bz2")
+	assert.NoError(t, os.WriteFile(path, data, 0o600))
+
+	r, err := Open(path)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a", "b"}, ReadLines(r).Slice())
+	assert.NoError(t, r.Close())
+}
+
+func TestOpenBzip2Lookalike(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "a.txt")
+	assert.NoError(t, os.WriteFile(path, []byte("BZhello\nworld\n"), 0o600))
+
+	r, err := Open(path)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"BZhello", "world"}, ReadLines(r).Slice())
+	assert.NoError(t, r.Close())
+}
+
+func TestCreateBzip2(t *testing.T) {
+	t.Parallel()
+
+	_, err := Create(filepath.Join(t.TempDir(), "a.bz2"))
+	assert.True(t, errors.Is(err, ErrUnsupportedCompression))
+}
@@ -0,0 +1,129 @@
+package text
+
+import (
+	"regexp"
+
+	"github.com/rprtr258/fun/iter"
+)
+
+// GrepLine is a line along with its 1-based number.
+type GrepLine struct {
+	No   int
+	Text string
+}
+
+// GrepMatch is a matched line along with its context.
+type GrepMatch struct {
+	GrepLine
+	// Spans are byte offsets of matches in line, as returned by regexp.FindAllStringIndex.
+	// Inverted matches have no spans.
+	Spans [][]int
+	// Before and After are context lines. Context lines shared between matches
+	// are included only once, like in GNU grep output, so Before contains only lines
+	// not already included into previous match.
+	Before, After []GrepLine
+	// Separated is true if context is requested and there are lines skipped between
+	// previous match context and this one, GNU grep prints "--" in such place.
+	Separated bool
+}
+
+// GrepOptions configures lines matching.
+type GrepOptions struct {
+	// Before and After are numbers of context lines before and after match.
+	Before, After int
+	// Invert selects non-matching lines.
+	Invert bool
+	// MaxCount stops matching after given number of matches, if positive.
+	// Context after last match is still included.
+	MaxCount int
+}
+
+// Grep yields lines matching regular expression along with their context lines.
+// Memory usage is bounded by context size.
+func Grep(lines iter.Seq[string], re *regexp.Regexp, opts GrepOptions) iter.Seq[GrepMatch] {
+	return func(yield func(GrepMatch) bool) {
+		var (
+			before    []GrepLine // context lines candidates not included into any match
+			pending   *GrepMatch // match collecting after context
+			afterLeft int
+			lastNo    int // last line included into any match
+			count     int
+			stopped   bool
+		)
+		emit := func() bool {
+			m := pending
+			pending = nil
+			if !yield(*m) {
+				stopped = true
+			}
+			return !stopped
+		}
+
+		lineNo := 0
+		lines(func(text string) bool {
+			lineNo++
+			line := GrepLine{lineNo, text}
+
+			isMatch := false
+			var spans [][]int
+			if opts.MaxCount <= 0 || count < opts.MaxCount {
+				if opts.Invert {
+					isMatch = !re.MatchString(text)
+				} else {
+					spans = re.FindAllStringIndex(text, -1)
+					isMatch = spans != nil
+				}
+			}
+
+			if isMatch {
+				if pending != nil && !emit() {
+					return false
+				}
+
+				first := lineNo
+				if len(before) > 0 {
+					first = before[0].No
+				}
+				pending = &GrepMatch{
+					GrepLine:  line,
+					Spans:     spans,
+					Before:    before,
+					Separated: (opts.Before > 0 || opts.After > 0) && lastNo > 0 && first > lastNo+1,
+				}
+				before = nil
+				afterLeft = opts.After
+				lastNo = lineNo
+				count++
+				return true
+			}
+
+			if pending != nil && afterLeft > 0 {
+				pending.After = append(pending.After, line)
+				afterLeft--
+				lastNo = lineNo
+				return true
+			}
+
+			if pending != nil && !emit() {
+				return false
+			}
+
+			if opts.MaxCount > 0 && count >= opts.MaxCount {
+				return false
+			}
+
+			if opts.Before > 0 {
+				if len(before) == opts.Before {
+					copy(before, before[1:])
+					before = before[:len(before)-1]
+				}
+				before = append(before, line)
+			}
+			return true
+		})
+
+		if pending != nil && !stopped {
+			emit()
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package text
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/rprtr258/assert"
+
+	"github.com/rprtr258/fun/iter"
+)
+
+// formatGrep renders matches like GNU grep -n does.
+func formatGrep(matches iter.Seq[GrepMatch]) string {
+	var sb strings.Builder
+	for m := range matches {
+		if m.Separated {
+			sb.WriteString("--\n")
+		}
+		for _, l := range m.Before {
+			sb.WriteString(strconv.Itoa(l.No) + "-" + l.Text + "\n")
+		}
+		sb.WriteString(strconv.Itoa(m.No) + ":" + m.Text + "\n")
+		for _, l := range m.After {
+			sb.WriteString(strconv.Itoa(l.No) + "-" + l.Text + "\n")
+		}
+	}
+	return sb.String()
+}
+
+var grepInput = iter.FromMany(strings.Split("a\nfoo1\nb\nc\nfoo2\nd\ne\nf\ng\nfoo3\nh", "\n")...)
+
+func TestGrep(t *testing.T) {
+	t.Parallel()
+
+	re := regexp.MustCompile(`foo(\d)`)
+	assert.Equal(t, "2:foo1\n5:foo2\n10:foo3\n", formatGrep(Grep(grepInput, re, GrepOptions{})))
+	assert.Equal(t, ""+
+		"1-a\n2:foo1\n3-b\n4-c\n5:foo2\n6-d\n"+
+		"--\n"+
+		"9-g\n10:foo3\n11-h\n",
+		formatGrep(Grep(grepInput, re, GrepOptions{Before: 1, After: 1})))
+	assert.Equal(t, ""+
+		"1-a\n2:foo1\n3-b\n4-c\n5:foo2\n6-d\n7-e\n"+
+		"8-f\n9-g\n10:foo3\n11-h\n",
+		formatGrep(Grep(grepInput, re, GrepOptions{Before: 2, After: 2})))
+
+	first, ok := Grep(grepInput, re, GrepOptions{}).Head()
+	assert.True(t, ok)
+	assert.Equal(t, [][]int{{0, 4}}, first.Spans)
+}
+
+func TestGrepInvertMaxCount(t *testing.T) {
+	t.Parallel()
+
+	re := regexp.MustCompile(`foo`)
+	assert.Equal(t, "1:a\n3:b\n4:c\n", formatGrep(Grep(grepInput, re, GrepOptions{Invert: true, MaxCount: 3})))
+	assert.Equal(t, "2:foo1\n3-b\n--\n5:foo2\n6-d\n", formatGrep(Grep(grepInput, re, GrepOptions{After: 1, MaxCount: 2})))
+}
@@ -0,0 +1,102 @@
+package text
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	xjson "github.com/rprtr258/fun/exp/json"
+	"github.com/rprtr258/fun/iter"
+)
+
+// JSONLError is an error in decoding line of JSON Lines stream.
+type JSONLError struct {
+	Line int
+	Err  error
+}
+
+func (e *JSONLError) Error() string {
+	return fmt.Sprintf("line %d: %v", e.Line, e.Err)
+}
+
+func (e *JSONLError) Unwrap() error {
+	return e.Err
+}
+
+// readJSONL decodes non-blank lines, calling onMalformed for lines failed to decode.
+// Decoding stops if onMalformed returns false. Reading error is always fatal.
+func readJSONL[T any](
+	r io.Reader,
+	decoder xjson.Decoder[T],
+	onMalformed func(*JSONLError) bool,
+) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		lineNo := 0
+		for line, err := range Lines(r) {
+			lineNo++
+			if err != nil {
+				var zero T
+				yield(zero, err)
+				return
+			}
+
+			if strings.TrimSpace(line) == "" {
+				continue
+			}
+
+			t, err := decoder.ParseString(line)
+			if err != nil {
+				if !onMalformed(&JSONLError{lineNo, err}) {
+					var zero T
+					yield(zero, &JSONLError{lineNo, err})
+					return
+				}
+				continue
+			}
+
+			if !yield(t, nil) {
+				return
+			}
+		}
+	}
+}
+
+// ReadJSONL reads JSON Lines stream, decoding each non-blank line using decoder.
+// Error, if any, is yielded as the last element, annotated with line number.
+func ReadJSONL[T any](r io.Reader, decoder xjson.Decoder[T]) iter.Seq2[T, error] {
+	return readJSONL(r, decoder, func(*JSONLError) bool { return false })
+}
+
+// ReadJSONLLenient reads JSON Lines stream, decoding each non-blank line using decoder.
+// Malformed lines are skipped and reported to onMalformed. Reading error, if any,
+// is yielded as the last element.
+func ReadJSONLLenient[T any](
+	r io.Reader,
+	decoder xjson.Decoder[T],
+	onMalformed func(*JSONLError),
+) iter.Seq2[T, error] {
+	return readJSONL(r, decoder, func(err *JSONLError) bool {
+		onMalformed(err)
+		return true
+	})
+}
+
+// WriteJSONL writes values as JSON Lines stream, each value is followed by \n.
+// Writing stops on first error, which is returned.
+func WriteJSONL[T any](w io.Writer, seq iter.Seq[T]) error {
+	bw := bufio.NewWriter(w)
+	for t := range seq {
+		b, err := json.Marshal(t)
+		if err != nil {
+			return err
+		}
+
+		b = append(b, endline...)
+		if _, err := bw.Write(b); err != nil {
+			return err
+		}
+	}
+	return bw.Flush()
+}
@@ -0,0 +1,71 @@
+package text
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/rprtr258/assert"
+
+	xjson "github.com/rprtr258/fun/exp/json"
+	"github.com/rprtr258/fun/iter"
+)
+
+type jsonlEvent struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+var jsonlEventDecoder = xjson.Map2(
+	func(id int, name string) jsonlEvent { return jsonlEvent{id, name} },
+	xjson.Int.Field("id"),
+	xjson.String.Field("name"),
+)
+
+const jsonlInput = `{"id": 1, "name": "a"}
+
+{"id": "2", "name": "b"}
+{"id": 3, "name": "c"}
+`
+
+func TestReadJSONL(t *testing.T) {
+	t.Parallel()
+
+	var (
+		got    []jsonlEvent
+		gotErr error
+	)
+	for e, err := range ReadJSONL(strings.NewReader(jsonlInput), jsonlEventDecoder) {
+		if err != nil {
+			gotErr = err
+			continue
+		}
+		got = append(got, e)
+	}
+	assert.Equal(t, []jsonlEvent{{1, "a"}}, got)
+	var jsonlErr *JSONLError
+	assert.True(t, errors.As(gotErr, &jsonlErr))
+	assert.Equal(t, 3, jsonlErr.Line)
+}
+
+func TestReadJSONLLenient(t *testing.T) {
+	t.Parallel()
+
+	var malformed []int
+	got := collectSeq2(t, ReadJSONLLenient(strings.NewReader(jsonlInput), jsonlEventDecoder, func(err *JSONLError) {
+		malformed = append(malformed, err.Line)
+	}))
+	assert.Equal(t, []jsonlEvent{{1, "a"}, {3, "c"}}, got)
+	assert.Equal(t, []int{3}, malformed)
+}
+
+func TestWriteJSONL(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	events := []jsonlEvent{{1, "a"}, {2, "b"}}
+	assert.NoError(t, WriteJSONL(&buf, iter.FromMany(events...)))
+	assert.Equal(t, "{\"id\":1,\"name\":\"a\"}\n{\"id\":2,\"name\":\"b\"}\n", buf.String())
+	assert.Equal(t, events, collectSeq2(t, ReadJSONL(&buf, jsonlEventDecoder)))
+}
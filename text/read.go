@@ -2,8 +2,12 @@
 package text
 
 import (
+	"bufio"
 	"bytes"
+	"errors"
+	"fmt"
 	"io"
+	"math"
 
 	"github.com/rprtr258/fun"
 	"github.com/rprtr258/fun/iter"
@@ -52,20 +56,92 @@ func SplitBySeparator(seq iter.Seq[[]byte], sep byte) iter.Seq[[]byte] {
 	}
 }
 
-// ReadLines reads text file line-by-line.
-func ReadLines(reader io.Reader) iter.Seq[string] {
-	chunks := ReadByteChunks(reader, defaultChunkSize)
+// ErrLineTooLong is returned when line exceeds maximum length.
+var ErrLineTooLong = errors.New("line too long")
+
+// LinesOptions configures line splitting.
+type LinesOptions struct {
+	// Separator between lines. If empty, lines are separated by \n
+	// with optional preceding \r, so both LF and CRLF line endings are supported.
+	Separator string
+	// MaxLength is maximum line length in bytes, not including separator.
+	// Zero means no limit.
+	MaxLength int
+}
+
+func (opts LinesOptions) split(data []byte, atEOF bool) (int, []byte, error) {
+	sep := []byte(opts.Separator)
+	if len(sep) == 0 {
+		sep = []byte{'\n'}
+	}
+
+	if idx := bytes.Index(data, sep); idx != -1 {
+		line := data[:idx]
+		if opts.Separator == "" {
+			line = bytes.TrimSuffix(line, []byte{'\r'})
+		}
+		if opts.MaxLength > 0 && len(line) > opts.MaxLength {
+			return 0, nil, ErrLineTooLong
+		}
+		return idx + len(sep), line, nil
+	}
+
+	// separator might be split between reads, so line can not be longer than that
+	if opts.MaxLength > 0 && len(data) > opts.MaxLength+len(sep) {
+		return 0, nil, ErrLineTooLong
+	}
+
+	if atEOF && len(data) != 0 {
+		line := data
+		if opts.Separator == "" {
+			line = bytes.TrimSuffix(line, []byte{'\r'})
+		}
+		if opts.MaxLength > 0 && len(line) > opts.MaxLength {
+			return 0, nil, ErrLineTooLong
+		}
+		return len(data), line, nil
+	}
+
+	// request more data
+	return 0, nil, nil
+}
 
-	pull, stop := chunks.Pull()
-	defer stop()
+// LinesWith reads text line-by-line, splitting it as configured by opts.
+// Last line is yielded only if it is not empty. Reading error, if any,
+// is yielded as the last element, annotated with number of line it happened on.
+func LinesWith(r io.Reader, opts LinesOptions) iter.Seq2[string, error] {
+	return func(yield func(string, error) bool) {
+		s := bufio.NewScanner(r)
+		s.Buffer(make([]byte, 0, defaultChunkSize), math.MaxInt)
+		s.Split(opts.split)
 
-	rows := SplitBySeparator(func(yield func([]byte) bool) {
-		for r, ok := pull(); ok; r, ok = pull() {
-			if r.V != nil || !yield(r.K) {
+		lineNo := 1
+		for ; s.Scan(); lineNo++ {
+			if !yield(s.Text(), nil) {
 				return
 			}
 		}
-	}, '\n')
 
-	return iter.Map(rows, func(x []byte) string { return string(x) })
+		if err := s.Err(); err != nil {
+			yield("", fmt.Errorf("line %d: %w", lineNo, err))
+		}
+	}
+}
+
+// Lines reads text line-by-line, supporting both LF and CRLF line endings.
+// Last line is yielded only if it is not empty. Reading error, if any, is yielded as the last element.
+func Lines(r io.Reader) iter.Seq2[string, error] {
+	return LinesWith(r, LinesOptions{})
+}
+
+// ReadLines reads text file line-by-line.
+// Reading stops silently on first error, use Lines to get it.
+func ReadLines(reader io.Reader) iter.Seq[string] {
+	return func(yield func(string) bool) {
+		for line, err := range Lines(reader) {
+			if err != nil || !yield(line) {
+				return
+			}
+		}
+	}
 }
@@ -0,0 +1,213 @@
+package text
+
+import (
+	"cmp"
+	"errors"
+	"io"
+	"os"
+	"slices"
+
+	"github.com/rprtr258/fun/iter"
+)
+
+const defaultSortMemory = 64 * 1024 * 1024 // 64 MB
+
+// SortOptions configures lines sorting.
+type SortOptions struct {
+	// MaxMemory is approximate size in bytes of lines held in memory at once.
+	// Input exceeding it is split into sorted runs stored in temporary files.
+	// Defaults to 64 MB.
+	MaxMemory int
+	// Compare compares lines keys, strings.Compare by default.
+	Compare func(string, string) int
+	// Key extracts sort key from line, whole line is used by default.
+	Key func(string) string
+	// Unique leaves only first of lines with equal keys.
+	Unique bool
+	// TempDir is directory for temporary files, os.TempDir by default.
+	TempDir string
+}
+
+// sortLine is line with its sort key, so key is computed once per line.
+type sortLine struct {
+	line, key string
+}
+
+func (opts SortOptions) keyed(line string) sortLine {
+	key := line
+	if opts.Key != nil {
+		key = opts.Key(line)
+	}
+	return sortLine{line, key}
+}
+
+func (opts SortOptions) compare(a, b sortLine) int {
+	if opts.Compare != nil {
+		return opts.Compare(a.key, b.key)
+	}
+	return cmp.Compare(a.key, b.key)
+}
+
+// mergeRuns lazily merges sorted runs preserving order of equal lines from different runs.
+func mergeRuns(runs []iter.Seq[sortLine], compare func(sortLine, sortLine) int) iter.Seq[sortLine] {
+	switch len(runs) {
+	case 0:
+		return iter.FromNothing[sortLine]()
+	case 1:
+		return runs[0]
+	default:
+		mid := len(runs) / 2
+		return mergeRuns(runs[:mid], compare).MergeFunc(mergeRuns(runs[mid:], compare), compare)
+	}
+}
+
+// writeRun writes sorted lines to new temporary file and returns its name.
+func writeRun(lines []sortLine, dir string) (string, error) {
+	f, err := os.CreateTemp(dir, "sortlines-*")
+	if err != nil {
+		return "", err
+	}
+
+	seq := iter.Map(iter.FromMany(lines...), func(l sortLine) string { return l.line })
+	if err := WriteLinesWith(f, seq, WriteLinesOptions{TrailingNewline: true}); err != nil {
+		return f.Name(), errors.Join(err, f.Close())
+	}
+	return f.Name(), f.Close()
+}
+
+// readRun reads lines of run file. Lines are split exactly by \n, so they are read back as written.
+// Reading error is stored to errp.
+func readRun(name string, opts SortOptions, errp *error) iter.Seq[sortLine] {
+	return func(yield func(sortLine) bool) {
+		f, err := os.Open(name)
+		if err != nil {
+			*errp = err
+			return
+		}
+		defer f.Close()
+
+		for line, err := range LinesWith(f, LinesOptions{Separator: "\n"}) {
+			if err != nil {
+				*errp = err
+				return
+			}
+
+			if !yield(opts.keyed(line)) {
+				return
+			}
+		}
+	}
+}
+
+// splitRuns reads lines from r, writing sorted runs exceeding maxMemory to temporary files.
+// Lines left are returned unsorted. Names of written files are returned even on error.
+func (opts SortOptions) splitRuns(r io.Reader) ([]sortLine, []string, error) {
+	maxMemory := opts.MaxMemory
+	if maxMemory <= 0 {
+		maxMemory = defaultSortMemory
+	}
+
+	var (
+		run      []sortLine
+		runSize  int
+		runNames []string
+	)
+	for line, err := range Lines(r) {
+		if err != nil {
+			return nil, runNames, err
+		}
+
+		run = append(run, opts.keyed(line))
+		runSize += len(line) + 32 // string headers
+		if runSize >= maxMemory {
+			slices.SortStableFunc(run, opts.compare)
+			name, err := writeRun(run, opts.TempDir)
+			if name != "" {
+				runNames = append(runNames, name)
+			}
+			if err != nil {
+				return nil, runNames, err
+			}
+			run, runSize = run[:0], 0
+		}
+	}
+	return run, runNames, nil
+}
+
+// SortedLines lazily yields sorted lines read from r. Sorting is stable.
+// Memory usage is bounded by opts.MaxMemory: larger input is split into sorted runs
+// written to temporary files, which are then lazily merged. Temporary files are removed
+// when iteration ends. Error, if any, is yielded as the last element.
+func SortedLines(r io.Reader, opts SortOptions) iter.Seq2[string, error] {
+	return func(yield func(string, error) bool) {
+		run, runNames, err := opts.splitRuns(r)
+		errs := []error{err}
+		stopped := false
+		if err == nil {
+			slices.SortStableFunc(run, opts.compare)
+			runErrs := make([]error, len(runNames))
+			runs := make([]iter.Seq[sortLine], len(runNames), len(runNames)+1)
+			for i, name := range runNames {
+				runs[i] = readRun(name, opts, &runErrs[i])
+			}
+			// lines left in memory are the last run, so stability is preserved
+			runs = append(runs, iter.FromMany(run...))
+
+			sorted := mergeRuns(runs, opts.compare)
+			if opts.Unique {
+				sorted = uniqueSorted(sorted, opts.compare)
+			}
+			sorted(func(l sortLine) bool {
+				stopped = !yield(l.line, nil)
+				return !stopped
+			})
+			errs = append(errs, runErrs...)
+		}
+
+		for _, name := range runNames {
+			errs = append(errs, os.Remove(name))
+		}
+		if err := errors.Join(errs...); err != nil && !stopped {
+			yield("", err)
+		}
+	}
+}
+
+// SortLines sorts lines read from r and writes them to w, each followed by \n.
+// Sorting is stable and memory usage is bounded as described in SortedLines.
+func SortLines(r io.Reader, w io.Writer, opts SortOptions) error {
+	var err error
+	sorted := func(yield func(string) bool) {
+		for line, errLine := range SortedLines(r, opts) {
+			if errLine != nil {
+				err = errLine
+				return
+			}
+
+			if !yield(line) {
+				return
+			}
+		}
+	}
+	if errWrite := WriteLinesWith(w, sorted, WriteLinesOptions{TrailingNewline: true}); errWrite != nil {
+		return errWrite
+	}
+	return err
+}
+
+// uniqueSorted leaves only first of consecutive lines with equal keys.
+func uniqueSorted(seq iter.Seq[sortLine], compare func(sortLine, sortLine) int) iter.Seq[sortLine] {
+	return func(yield func(sortLine) bool) {
+		var (
+			prev    sortLine
+			hasPrev bool
+		)
+		seq(func(line sortLine) bool {
+			if hasPrev && compare(prev, line) == 0 {
+				return true
+			}
+			prev, hasPrev = line, true
+			return yield(line)
+		})
+	}
+}
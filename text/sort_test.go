@@ -0,0 +1,126 @@
+package text
+
+import (
+	"bytes"
+	"cmp"
+	"fmt"
+	"math/rand/v2"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/rprtr258/assert"
+)
+
+func TestSortLines(t *testing.T) {
+	t.Parallel()
+
+	var out bytes.Buffer
+	assert.NoError(t, SortLines(strings.NewReader("c\na\nb\n"), &out, SortOptions{}))
+	assert.Equal(t, "a\nb\nc\n", out.String())
+}
+
+func TestSortLinesExternal(t *testing.T) {
+	t.Parallel()
+
+	rng := rand.New(rand.NewPCG(1, 2))
+	lines := make([]string, 1000)
+	for i := range lines {
+		lines[i] = strconv.Itoa(rng.IntN(500))
+	}
+
+	dir := t.TempDir()
+	var out bytes.Buffer
+	assert.NoError(t, SortLines(strings.NewReader(strings.Join(lines, "\n")), &out, SortOptions{
+		MaxMemory: 500,
+		TempDir:   dir,
+	}))
+	slices.Sort(lines)
+	assert.Equal(t, strings.Join(lines, "\n")+"\n", out.String())
+
+	entries, err := os.ReadDir(dir)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, len(entries))
+}
+
+func TestSortLinesKeyUnique(t *testing.T) {
+	t.Parallel()
+
+	input := "3 c\n1 a\n2 b\n1 aa\n3 cc\n10 j\n"
+	var out bytes.Buffer
+	assert.NoError(t, SortLines(strings.NewReader(input), &out, SortOptions{
+		MaxMemory: 20,
+		TempDir:   t.TempDir(),
+		Key: func(line string) string {
+			key, _, _ := strings.Cut(line, " ")
+			return key
+		},
+		Compare: func(a, b string) int {
+			x, _ := strconv.Atoi(a)
+			y, _ := strconv.Atoi(b)
+			return cmp.Compare(x, y)
+		},
+		Unique: true,
+	}))
+	assert.Equal(t, "1 a\n2 b\n3 c\n10 j\n", out.String())
+}
+
+func TestSortLinesCarriageReturn(t *testing.T) {
+	t.Parallel()
+
+	input := "b\r\r\nc\na\r\n"
+	for _, maxMemory := range []int{0, 1} {
+		var out bytes.Buffer
+		assert.NoError(t, SortLines(strings.NewReader(input), &out, SortOptions{
+			MaxMemory: maxMemory,
+			TempDir:   t.TempDir(),
+		}))
+		assert.Equal(t, "a\nb\r\nc\n", out.String())
+	}
+}
+
+func TestSortedLines(t *testing.T) {
+	t.Parallel()
+
+	keyCalls := 0
+	opts := SortOptions{
+		MaxMemory: 100,
+		TempDir:   t.TempDir(),
+		Key: func(line string) string {
+			keyCalls++
+			return line
+		},
+	}
+	lines := make([]string, 100)
+	for i := range lines {
+		lines[i] = strconv.Itoa(99 - i)
+	}
+
+	got := collectSeq2(t, SortedLines(strings.NewReader(strings.Join(lines, "\n")), opts))
+	slices.Sort(lines)
+	assert.Equal(t, lines, got)
+	// once on reading input and once on reading run back
+	assert.True(t, keyCalls <= 2*len(lines))
+
+	// stopping iteration early removes runs
+	opts.MaxMemory = 1
+	for line, err := range SortedLines(strings.NewReader("b\na\n"), opts) {
+		assert.NoError(t, err)
+		assert.Equal(t, "a", line)
+		break
+	}
+	entries, err := os.ReadDir(opts.TempDir)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, len(entries))
+}
+
+func ExampleSortLines() {
+	var out bytes.Buffer
+	_ = SortLines(strings.NewReader("b\na\nb\n"), &out, SortOptions{Unique: true})
+	fmt.Print(out.String())
+	// Output:
+	// a
+	// b
+}
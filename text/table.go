@@ -0,0 +1,260 @@
+package text
+
+import (
+	"bufio"
+	"encoding/csv"
+	"io"
+	"strings"
+
+	"github.com/rprtr258/fun/iter"
+)
+
+// Align is a column content alignment.
+type Align int
+
+const (
+	AlignLeft Align = iota
+	AlignRight
+	AlignCenter
+)
+
+// TableFormat is a table output format.
+type TableFormat int
+
+const (
+	// TableFormatPlain renders columns aligned by spaces.
+	TableFormatPlain TableFormat = iota
+	// TableFormatMarkdown renders GitHub flavored Markdown table.
+	TableFormatMarkdown
+	// TableFormatCSV renders CSV, alignment and widths are ignored.
+	TableFormatCSV
+)
+
+// Column describes table column.
+type Column struct {
+	Header string
+	Align  Align
+	// MaxWidth limits column display width, if positive.
+	MaxWidth int
+}
+
+// TableOptions configures table rendering.
+type TableOptions struct {
+	Format TableFormat
+	// Wrap makes cells exceeding column width wrap to multiple lines instead of being truncated.
+	// Markdown cell lines are joined by <br>.
+	Wrap bool
+	// StreamRows, if positive, fixes column widths from header and first StreamRows rows,
+	// so only those rows are held in memory. Otherwise all rows are read before rendering.
+	// Later cells wider than their column are truncated or wrapped only if column has MaxWidth,
+	// otherwise they are written whole and break alignment of the row.
+	StreamRows int
+}
+
+// tableWriter renders rows with fixed column widths.
+type tableWriter struct {
+	w       *bufio.Writer
+	columns []Column
+	widths  []int
+	opts    TableOptions
+}
+
+func (t *tableWriter) cellLines(cell string, i int) []string {
+	width := t.columns[i].MaxWidth
+	if width <= 0 {
+		return []string{cell}
+	}
+	if t.opts.Wrap {
+		return wrap(cell, width)
+	}
+	return []string{truncate(cell, width)}
+}
+
+// cell returns i-th cell of row as it is rendered, before fitting into column width.
+func (t *tableWriter) cell(row []string, i int) string {
+	if i >= len(row) {
+		return ""
+	}
+	if t.opts.Format == TableFormatMarkdown {
+		return strings.ReplaceAll(row[i], "|", `\|`)
+	}
+	return row[i]
+}
+
+func (t *tableWriter) writeRow(row []string) error {
+	cells := make([][]string, len(t.columns))
+	height := 1
+	for i := range t.columns {
+		cells[i] = t.cellLines(t.cell(row, i), i)
+		height = max(height, len(cells[i]))
+	}
+
+	if t.opts.Format == TableFormatMarkdown {
+		var sb strings.Builder
+		sb.WriteString("|")
+		for i, c := range cells {
+			sb.WriteString(" " + pad(strings.Join(c, "<br>"), t.widths[i], t.columns[i].Align) + " |")
+		}
+		_, err := t.w.WriteString(sb.String() + endline)
+		return err
+	}
+
+	for l := range height {
+		var sb strings.Builder
+		for i, c := range cells {
+			if i > 0 {
+				sb.WriteString("  ")
+			}
+			line := ""
+			if l < len(c) {
+				line = c[l]
+			}
+			sb.WriteString(pad(line, t.widths[i], t.columns[i].Align))
+		}
+		if _, err := t.w.WriteString(strings.TrimRight(sb.String(), " ") + endline); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t *tableWriter) writeHeader() error {
+	header := make([]string, len(t.columns))
+	for i, c := range t.columns {
+		header[i] = c.Header
+	}
+	if err := t.writeRow(header); err != nil {
+		return err
+	}
+
+	var sb strings.Builder
+	for i, c := range t.columns {
+		switch t.opts.Format {
+		case TableFormatMarkdown:
+			dashes := strings.Repeat("-", t.widths[i])
+			switch c.Align {
+			case AlignRight:
+				dashes = dashes[1:] + ":"
+			case AlignCenter:
+				dashes = ":" + dashes[2:] + ":"
+			}
+			if i == 0 {
+				sb.WriteString("|")
+			}
+			sb.WriteString(" " + dashes + " |")
+		default:
+			if i > 0 {
+				sb.WriteString("  ")
+			}
+			sb.WriteString(strings.Repeat("-", t.widths[i]))
+		}
+	}
+	_, err := t.w.WriteString(sb.String() + endline)
+	return err
+}
+
+// Table renders rows as plain text table with given header and left aligned columns.
+func Table(header []string, rows iter.Seq[[]string]) string {
+	columns := make([]Column, len(header))
+	for i, h := range header {
+		columns[i] = Column{Header: h}
+	}
+
+	var sb strings.Builder
+	_ = WriteTable(&sb, columns, rows, TableOptions{}) // strings.Builder never fails
+	return sb.String()
+}
+
+// WriteTable renders rows as table with given columns.
+// Column widths are measured in terminal cells, so wide characters are aligned properly.
+// Cells exceeding column MaxWidth are truncated or wrapped.
+func WriteTable(w io.Writer, columns []Column, rows iter.Seq[[]string], opts TableOptions) error {
+	if opts.Format == TableFormatCSV {
+		return writeTableCSV(w, columns, rows)
+	}
+
+	t := &tableWriter{
+		w:       bufio.NewWriter(w),
+		columns: columns,
+		widths:  make([]int, len(columns)),
+		opts:    opts,
+	}
+
+	// measure widths on buffered rows
+	var buffered [][]string
+	measure := func() {
+		for i, c := range columns {
+			t.widths[i] = Width(t.cell([]string{c.Header}, 0))
+			for _, row := range buffered {
+				t.widths[i] = max(t.widths[i], Width(t.cell(row, i)))
+			}
+			if c.MaxWidth > 0 {
+				t.widths[i] = min(t.widths[i], c.MaxWidth)
+			}
+			if opts.Format == TableFormatMarkdown {
+				// delimiter row needs room for alignment colons
+				t.widths[i] = max(t.widths[i], 3)
+			}
+		}
+	}
+	headerWritten := false
+	writeBuffered := func() error {
+		measure()
+		headerWritten = true
+		if err := t.writeHeader(); err != nil {
+			return err
+		}
+		for _, row := range buffered {
+			if err := t.writeRow(row); err != nil {
+				return err
+			}
+		}
+		buffered = nil
+		return nil
+	}
+
+	var err error
+	rows(func(row []string) bool {
+		if !headerWritten && (opts.StreamRows <= 0 || len(buffered) < opts.StreamRows) {
+			buffered = append(buffered, row)
+			return true
+		}
+
+		if !headerWritten {
+			if err = writeBuffered(); err != nil {
+				return false
+			}
+		}
+		err = t.writeRow(row)
+		return err == nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if !headerWritten {
+		if err := writeBuffered(); err != nil {
+			return err
+		}
+	}
+	return t.w.Flush()
+}
+
+func writeTableCSV(w io.Writer, columns []Column, rows iter.Seq[[]string]) error {
+	cw := csv.NewWriter(w)
+	header := make([]string, len(columns))
+	for i, c := range columns {
+		header[i] = c.Header
+	}
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+
+	for row := range rows {
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
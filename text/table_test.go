@@ -0,0 +1,124 @@
+package text
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/rprtr258/assert"
+
+	"github.com/rprtr258/fun/iter"
+)
+
+var tableColumns = []Column{
+	{Header: "name"},
+	{Header: "count", Align: AlignRight},
+	{Header: "note", Align: AlignCenter, MaxWidth: 8},
+}
+
+var tableRows = iter.FromMany(
+	[]string{"apple", "3", "red"},
+	[]string{"日本", "12", "very long note"},
+	[]string{"x|y", "100"},
+)
+
+func renderTable(t *testing.T, rows iter.Seq[[]string], opts TableOptions) string {
+	t.Helper()
+	var buf bytes.Buffer
+	assert.NoError(t, WriteTable(&buf, tableColumns, rows, opts))
+	return buf.String()
+}
+
+func TestWidth(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, 5, Width("hello"))
+	assert.Equal(t, 4, Width("日本"))
+	assert.Equal(t, 1, Width("é"))
+}
+
+func TestWriteTable(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, ""+
+		"name   count    note\n"+
+		"-----  -----  --------\n"+
+		"apple      3    red\n"+
+		"日本      12  very lo…\n"+
+		"x|y      100\n",
+		renderTable(t, tableRows, TableOptions{}))
+
+	assert.Equal(t, ""+
+		"name   count    note\n"+
+		"-----  -----  --------\n"+
+		"apple      3    red\n"+
+		"日本      12    very\n"+
+		"                long\n"+
+		"                note\n"+
+		"x|y      100\n",
+		renderTable(t, tableRows, TableOptions{Wrap: true}))
+}
+
+func TestTable(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, ""+
+		"a     b\n"+
+		"----  -\n"+
+		"x     y\n"+
+		"long  z\n",
+		Table([]string{"a", "b"}, iter.FromMany([]string{"x", "y"}, []string{"long", "z"})))
+}
+
+func TestWriteTableMarkdown(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, ""+
+		"| name  | count |   note   |\n"+
+		"| ----- | ----: | :------: |\n"+
+		"| apple |     3 |   red    |\n"+
+		"| 日本  |    12 | very lo… |\n"+
+		"| x\\|y  |   100 |          |\n",
+		renderTable(t, tableRows, TableOptions{Format: TableFormatMarkdown}))
+}
+
+func TestWriteTableMarkdownEscapedWidest(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	assert.NoError(t, WriteTable(&buf, []Column{{Header: "k"}, {Header: "v"}}, iter.FromMany(
+		[]string{"a|b", "1"},
+	), TableOptions{Format: TableFormatMarkdown}))
+	assert.Equal(t, ""+
+		"| k    | v   |\n"+
+		"| ---- | --- |\n"+
+		"| a\\|b | 1   |\n",
+		buf.String())
+}
+
+func TestWriteTableCSV(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, ""+
+		"name,count,note\n"+
+		"apple,3,red\n"+
+		"日本,12,very long note\n"+
+		"x|y,100\n",
+		renderTable(t, tableRows, TableOptions{Format: TableFormatCSV}))
+}
+
+func TestWriteTableStream(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, ""+
+		"name   count  note\n"+
+		"-----  -----  ----\n"+
+		"apple      3  red\n"+
+		"日本      12  very lo…\n"+
+		"x|y      100\n",
+		renderTable(t, tableRows, TableOptions{StreamRows: 1}))
+
+	assert.Equal(t, ""+
+		"name  count  note\n"+
+		"----  -----  ----\n",
+		renderTable(t, iter.FromNothing[[]string](), TableOptions{StreamRows: 1}))
+}
@@ -0,0 +1,234 @@
+package text
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/rprtr258/fun/iter"
+)
+
+// readerAt makes io.ReaderAt from io.ReadSeeker which does not implement it.
+type readerAt struct {
+	io.ReadSeeker
+}
+
+func (r readerAt) ReadAt(b []byte, off int64) (int, error) {
+	if _, err := r.Seek(off, io.SeekStart); err != nil {
+		return 0, err
+	}
+	return io.ReadFull(r, b)
+}
+
+// ReadLinesReverse reads text line-by-line starting from the last line.
+// File is read in chunks from the end, so getting last lines of big file is cheap.
+// Both LF and CRLF line endings are supported. Reading error, if any, is yielded as the last element.
+func ReadLinesReverse(r io.ReadSeeker) iter.Seq2[string, error] {
+	return func(yield func(string, error) bool) {
+		size, err := r.Seek(0, io.SeekEnd)
+		if err != nil {
+			yield("", err)
+			return
+		}
+
+		ra, ok := r.(io.ReaderAt)
+		if !ok {
+			ra = readerAt{r}
+		}
+
+		var (
+			parts   [][]byte // pieces of line following pos, last piece first
+			isFirst = true
+			chunk   = make([]byte, defaultChunkSize)
+		)
+		yieldLine := func(parts [][]byte) bool {
+			var line strings.Builder
+			for i := len(parts) - 1; i >= 0; i-- {
+				line.Write(parts[i])
+			}
+			// file ending with newline does not have empty last line
+			if isFirst && line.Len() == 0 {
+				isFirst = false
+				return true
+			}
+			isFirst = false
+			return yield(strings.TrimSuffix(line.String(), "\r"), nil)
+		}
+		for pos := size; pos > 0; {
+			n := min(int64(len(chunk)), pos)
+			pos -= n
+			if _, err := ra.ReadAt(chunk[:n], pos); err != nil && !errors.Is(err, io.EOF) {
+				yield("", err)
+				return
+			}
+
+			rest := chunk[:n]
+			for idx := bytes.LastIndexByte(rest, '\n'); idx != -1; idx = bytes.LastIndexByte(rest, '\n') {
+				if !yieldLine(append(parts, rest[idx+1:])) {
+					return
+				}
+				parts = parts[:0]
+				rest = rest[:idx]
+			}
+			if len(rest) != 0 {
+				// line continues in previous chunk, keep this one and read into new buffer
+				parts = append(parts, rest)
+				chunk = make([]byte, defaultChunkSize)
+			}
+		}
+
+		if size != 0 {
+			yieldLine(parts)
+		}
+	}
+}
+
+const defaultPollInterval = time.Second
+
+// FollowOptions configures file following.
+type FollowOptions struct {
+	// PollInterval is interval between checks for new data, 1 second by default.
+	PollInterval time.Duration
+	// FromStart makes following start from the beginning of the file instead of its end.
+	FromStart bool
+}
+
+// Follow yields lines appended to file, like tail -F does, until ctx is done.
+// Truncated file is read from the beginning again. If file is replaced, e.g. rotated,
+// rest of the old file is read and then new one is followed from the beginning.
+// Missing file is waited for. Only complete lines, ending with \n, are yielded.
+// Errors are yielded, following stops on first one.
+func Follow(ctx context.Context, path string, opts FollowOptions) iter.Seq2[string, error] {
+	interval := opts.PollInterval
+	if interval <= 0 {
+		interval = defaultPollInterval
+	}
+
+	return func(yield func(string, error) bool) {
+		var (
+			f       *os.File
+			br      *bufio.Reader
+			offset  int64
+			pending strings.Builder // incomplete last line
+		)
+		defer func() {
+			if f != nil {
+				f.Close()
+			}
+		}()
+
+		open := func(fromStart bool) error {
+			var err error
+			f, err = os.Open(path)
+			if errors.Is(err, os.ErrNotExist) {
+				f = nil
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+
+			offset = 0
+			if !fromStart {
+				if offset, err = f.Seek(0, io.SeekEnd); err != nil {
+					return err
+				}
+			}
+			br = bufio.NewReader(f)
+			pending.Reset()
+			return nil
+		}
+
+		// readAvailable yields all complete lines available and reports whether to continue.
+		readAvailable := func() bool {
+			for {
+				line, err := br.ReadString('\n')
+				offset += int64(len(line))
+				pending.WriteString(line)
+				if err != nil {
+					if errors.Is(err, io.EOF) {
+						return true
+					}
+					yield("", err)
+					return false
+				}
+
+				line = strings.TrimSuffix(strings.TrimSuffix(pending.String(), "\n"), "\r")
+				pending.Reset()
+				if !yield(line, nil) {
+					return false
+				}
+			}
+		}
+
+		if err := open(opts.FromStart); err != nil {
+			yield("", err)
+			return
+		}
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			if f != nil && !readAvailable() {
+				return
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			if f == nil {
+				if err := open(true); err != nil {
+					yield("", err)
+					return
+				}
+				continue
+			}
+
+			stat, err := os.Stat(path)
+			if errors.Is(err, os.ErrNotExist) {
+				// file is being rotated, keep reading old one until new appears
+				continue
+			}
+			if err != nil {
+				yield("", err)
+				return
+			}
+
+			fstat, err := f.Stat()
+			if err != nil {
+				yield("", err)
+				return
+			}
+
+			switch {
+			case !os.SameFile(stat, fstat):
+				// rotated: finish old file and switch to new one
+				if !readAvailable() {
+					return
+				}
+				f.Close()
+				if err := open(true); err != nil {
+					yield("", err)
+					return
+				}
+			case fstat.Size() < offset:
+				// truncated: start over
+				if _, err := f.Seek(0, io.SeekStart); err != nil {
+					yield("", err)
+					return
+				}
+				offset = 0
+				br.Reset(f)
+				pending.Reset()
+			}
+		}
+	}
+}
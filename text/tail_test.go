@@ -0,0 +1,132 @@
+package text
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rprtr258/assert"
+)
+
+// readSeeker hides io.ReaderAt implementation.
+type readSeeker struct {
+	io.ReadSeeker
+}
+
+func TestReadLinesReverse(t *testing.T) {
+	t.Parallel()
+
+	for input, want := range map[string][]string{
+		"":             {},
+		"\n":           {""},
+		"a":            {"a"},
+		"a\r\nb\n":     {"b", "a"},
+		"a\n\nb":       {"b", "", "a"},
+		exampleText:    {"Line 30", "Line 2", ""},
+		"\n\nlast\n\n": {"", "last", "", ""},
+	} {
+		assert.Equal(t, want, collectSeq2(t, ReadLinesReverse(strings.NewReader(input))))
+		assert.Equal(t, want, collectSeq2(t, ReadLinesReverse(readSeeker{strings.NewReader(input)})))
+	}
+}
+
+func TestReadLinesReverseLong(t *testing.T) {
+	t.Parallel()
+
+	lines := make([]string, 3000)
+	for i := range lines {
+		lines[i] = strconv.Itoa(i)
+	}
+	input := strings.Join(lines, "\n")
+
+	got := collectSeq2(t, ReadLinesReverse(strings.NewReader(input)))
+	slices.Reverse(got)
+	assert.Equal(t, lines, got)
+
+	last := []string{}
+	for line, err := range ReadLinesReverse(strings.NewReader(input)) {
+		assert.NoError(t, err)
+		last = append(last, line)
+		if len(last) == 3 {
+			break
+		}
+	}
+	assert.Equal(t, []string{"2999", "2998", "2997"}, last)
+
+	long := strings.Repeat("x", 5*defaultChunkSize+7)
+	assert.Equal(t, []string{"b", long, "a"}, collectSeq2(t, ReadLinesReverse(strings.NewReader("a\n"+long+"\r\nb"))))
+}
+
+func appendFile(t *testing.T, path, data string) {
+	t.Helper()
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	assert.NoError(t, err)
+	_, err = f.WriteString(data)
+	assert.NoError(t, err)
+	assert.NoError(t, f.Close())
+}
+
+func TestFollow(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "log")
+	appendFile(t, path, "old\n")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	steps := []func(){
+		func() {
+			// line is appended in parts, while being followed
+			go func() {
+				appendFile(t, path, "par")
+				time.Sleep(50 * time.Millisecond)
+				appendFile(t, path, "tial\r\n")
+			}()
+		},
+		func() {
+			// truncate and write shorter content
+			assert.NoError(t, os.WriteFile(path, []byte("t\n"), 0o600))
+		},
+		func() {
+			appendFile(t, path, "before rotation\n")
+			assert.NoError(t, os.Rename(path, path+".1"))
+			appendFile(t, path, "rotated\n")
+		},
+	}
+
+	got := []string{}
+	for line, err := range Follow(ctx, path, FollowOptions{PollInterval: 10 * time.Millisecond, FromStart: true}) {
+		assert.NoError(t, err)
+		got = append(got, line)
+		if len(got) == 5 {
+			break
+		}
+
+		if len(got) <= len(steps) {
+			steps[len(got)-1]()
+		}
+	}
+	assert.Equal(t, []string{"old", "partial", "t", "before rotation", "rotated"}, got)
+}
+
+func TestFollowCancel(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "missing")
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	got := []string{}
+	for line, err := range Follow(ctx, path, FollowOptions{PollInterval: 10 * time.Millisecond, FromStart: true}) {
+		assert.NoError(t, err)
+		got = append(got, line)
+	}
+	assert.Equal(t, []string{}, got)
+}
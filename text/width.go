@@ -0,0 +1,132 @@
+package text
+
+import (
+	"strings"
+	"unicode"
+)
+
+// wideRanges are East Asian Wide and Fullwidth character ranges, taking two terminal cells.
+var wideRanges = [][2]rune{
+	{0x1100, 0x115F},   // Hangul Jamo
+	{0x2E80, 0x303E},   // CJK radicals, punctuation
+	{0x3041, 0x33FF},   // Hiragana, Katakana, CJK compatibility
+	{0x3400, 0x4DBF},   // CJK unified ideographs extension A
+	{0x4E00, 0x9FFF},   // CJK unified ideographs
+	{0xA000, 0xA4CF},   // Yi
+	{0xAC00, 0xD7A3},   // Hangul syllables
+	{0xF900, 0xFAFF},   // CJK compatibility ideographs
+	{0xFE30, 0xFE4F},   // CJK compatibility forms
+	{0xFF00, 0xFF60},   // fullwidth forms
+	{0xFFE0, 0xFFE6},   // fullwidth signs
+	{0x1F300, 0x1F64F}, // pictographs, emoticons
+	{0x1F900, 0x1F9FF}, // supplemental pictographs
+	{0x20000, 0x3FFFD}, // CJK unified ideographs extensions
+}
+
+// runeWidth returns number of terminal cells taken by rune.
+func runeWidth(r rune) int {
+	switch {
+	case r == 0 || unicode.Is(unicode.Mn, r) || unicode.Is(unicode.Me, r) || unicode.Is(unicode.Cf, r):
+		return 0
+	case r < 0x1100:
+		return 1
+	}
+
+	for _, rng := range wideRanges {
+		if r >= rng[0] && r <= rng[1] {
+			return 2
+		}
+	}
+	return 1
+}
+
+// Width returns display width of string in terminal cells.
+// Wide East Asian characters take two cells, combining marks take none.
+func Width(s string) int {
+	res := 0
+	for _, r := range s {
+		res += runeWidth(r)
+	}
+	return res
+}
+
+// truncate cuts string to given display width, marking cut with ellipsis.
+func truncate(s string, width int) string {
+	if Width(s) <= width {
+		return s
+	}
+	if width <= 0 {
+		return ""
+	}
+
+	var sb strings.Builder
+	w := 0
+	for _, r := range s {
+		rw := runeWidth(r)
+		if w+rw > width-1 {
+			break
+		}
+		sb.WriteRune(r)
+		w += rw
+	}
+	sb.WriteRune('…')
+	return sb.String()
+}
+
+// wrap splits string into lines of at most given display width, breaking at spaces if possible.
+func wrap(s string, width int) []string {
+	if width <= 0 || Width(s) <= width {
+		return []string{s}
+	}
+
+	var (
+		lines []string
+		line  strings.Builder
+		lineW int
+	)
+	flush := func() {
+		lines = append(lines, line.String())
+		line.Reset()
+		lineW = 0
+	}
+	for i, word := range strings.Split(s, " ") {
+		wordW := Width(word)
+		if i > 0 {
+			if lineW+1+wordW <= width {
+				line.WriteByte(' ')
+				lineW++
+			} else {
+				flush()
+			}
+		}
+
+		// hard split of words longer than line
+		for _, r := range word {
+			rw := runeWidth(r)
+			if lineW+rw > width && lineW > 0 {
+				flush()
+			}
+			line.WriteRune(r)
+			lineW += rw
+		}
+	}
+	flush()
+	return lines
+}
+
+// pad aligns string within given display width.
+func pad(s string, width int, align Align) string {
+	gap := width - Width(s)
+	if gap <= 0 {
+		return s
+	}
+
+	switch align {
+	case AlignRight:
+		return strings.Repeat(" ", gap) + s
+	case AlignCenter:
+		return strings.Repeat(" ", gap/2) + s + strings.Repeat(" ", gap-gap/2)
+	default:
+		return s + strings.Repeat(" ", gap)
+	}
+}
@@ -1,8 +1,8 @@
 package text
 
 import (
+	"bufio"
 	"io"
-	"log"
 
 	s "github.com/rprtr258/fun/iter"
 )
@@ -10,15 +10,15 @@ import (
 var endline = "\n"
 
 // WriteByteChunks writes byte chunks to writer.
-func WriteByteChunks(writer io.Writer, xs s.Seq[[]byte]) {
-	xs.ForEach(func(chunk []byte) {
-		cnt, err := writer.Write(chunk)
-		if err != nil {
-			log.Printf("error writing to %v: %v\n", writer, err)
-		} else if cnt != len(chunk) {
-			log.Printf("only %d out of %d bytes were written\n", cnt, len(chunk))
+// Writing is buffered and stops on first error, which is returned.
+func WriteByteChunks(writer io.Writer, xs s.Seq[[]byte]) error {
+	w := bufio.NewWriter(writer)
+	for chunk := range xs {
+		if _, err := w.Write(chunk); err != nil {
+			return err
 		}
-	})
+	}
+	return w.Flush()
 }
 
 // MapStringToBytes converts stream of strings to stream of byte chunks.
@@ -26,16 +26,41 @@ func MapStringToBytes(stm s.Seq[string]) s.Seq[[]byte] {
 	return s.Map(stm, func(s string) []byte { return []byte(s) })
 }
 
-// WriteLines creates a sink that receives strings and saves them to writer.
-// It adds \n after each line.
-func WriteLines(writer io.Writer, xs s.Seq[string]) {
-	xs.
-		Intersperse(endline).
-		ForEach(func(chunk string) {
-			s := []byte(chunk)
-			_, err := writer.Write(s)
-			if err != nil {
-				log.Printf("error writing to %v: %v\n", writer, err)
+// WriteLinesOptions configures lines writing.
+type WriteLinesOptions struct {
+	// TrailingNewline adds \n after the last line too.
+	TrailingNewline bool
+}
+
+// WriteLinesWith writes strings to writer, separating them by \n.
+// Writing is buffered and stops on first error, which is returned.
+func WriteLinesWith(writer io.Writer, xs s.Seq[string], opts WriteLinesOptions) error {
+	w := bufio.NewWriter(writer)
+	isFirst := true
+	for line := range xs {
+		if !isFirst {
+			if _, err := w.WriteString(endline); err != nil {
+				return err
 			}
-		})
+		}
+		isFirst = false
+
+		if _, err := w.WriteString(line); err != nil {
+			return err
+		}
+	}
+
+	if opts.TrailingNewline && !isFirst {
+		if _, err := w.WriteString(endline); err != nil {
+			return err
+		}
+	}
+	return w.Flush()
+}
+
+// WriteLines creates a sink that receives strings and saves them to writer.
+// It adds \n between lines, but not after the last one.
+// Writing is buffered and stops on first error, which is returned.
+func WriteLines(writer io.Writer, xs s.Seq[string]) error {
+	return WriteLinesWith(writer, xs, WriteLinesOptions{})
 }